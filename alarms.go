@@ -0,0 +1,75 @@
+package sdwan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GetAlarms retrieves all alarms raised between two points in time, optionally limited to the given severities,
+// e.g. "Critical" or "Major". Pagination is handled transparently by following the scrollId of each page.
+//
+//	alarms, err := client.GetAlarms([]string{"Critical"}, time.Now().Add(-time.Hour), time.Now())
+func (client *Client) GetAlarms(severity []string, from, to time.Time, mods ...func(*Req)) ([]Res, error) {
+	q := StatisticsQuery{}.Rule("entry_time", "between", "date", epochMillis(from), epochMillis(to))
+	if len(severity) > 0 {
+		q = q.Rule("severity", "in", "string", severity...)
+	}
+	return client.scrollQuery("/alarms", q.Body().Str, mods...)
+}
+
+// AcknowledgeAlarms marks alarms as viewed (acknowledged) by their UUIDs. Every alarm is acknowledged
+// with its own request, so that a single failing alarm does not prevent the others from being acknowledged.
+// If any alarm fails, an error listing all of them is returned, wrapping the error of the first one.
+//
+//	err := client.AcknowledgeAlarms([]string{"A1", "A2"})
+func (client *Client) AcknowledgeAlarms(alarmUUIDs []string, mods ...func(*Req)) error {
+	return client.alarmAction("/alarms/markviewed", "acknowledged", alarmUUIDs, mods...)
+}
+
+// DismissAlarms clears alarms by their UUIDs, removing them from the list of active alarms.
+// Failures are reported per alarm, in the same way as AcknowledgeAlarms.
+//
+//	err := client.DismissAlarms([]string{"A1", "A2"})
+func (client *Client) DismissAlarms(alarmUUIDs []string, mods ...func(*Req)) error {
+	return client.alarmAction("/alarms/clear", "dismissed", alarmUUIDs, mods...)
+}
+
+// alarmAction validates a list of alarm UUIDs and posts each of them to an alarm action endpoint.
+func (client *Client) alarmAction(path, action string, alarmUUIDs []string, mods ...func(*Req)) error {
+	if len(alarmUUIDs) == 0 {
+		return fmt.Errorf("no alarm UUIDs given")
+	}
+	seen := make(map[string]bool, len(alarmUUIDs))
+	for _, uuid := range alarmUUIDs {
+		if strings.TrimSpace(uuid) == "" {
+			return fmt.Errorf("invalid alarm UUID: %q", uuid)
+		}
+		if seen[uuid] {
+			return fmt.Errorf("duplicate alarm UUID: %s", uuid)
+		}
+		seen[uuid] = true
+	}
+
+	var first error
+	failed := []string{}
+	for _, uuid := range alarmUUIDs {
+		body := Body{}.Set("alarm_uuid.-1", uuid)
+		if _, err := client.Post(path, body.Str, mods...); err != nil {
+			if first == nil {
+				first = fmt.Errorf("alarm %s: %w", uuid, err)
+			}
+			failed = append(failed, uuid)
+		}
+	}
+	if first == nil {
+		return nil
+	}
+	return fmt.Errorf("%d of %d alarms not %s (%s): %w", len(failed), len(alarmUUIDs), action, strings.Join(failed, ", "), first)
+}
+
+// epochMillis formats a point in time as epoch milliseconds, the format expected by vManage queries.
+func epochMillis(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano()/int64(time.Millisecond), 10)
+}
@@ -0,0 +1,67 @@
+package sdwan
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientGetAlarms tests the Client::GetAlarms method.
+func TestClientGetAlarms(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	from := time.UnixMilli(1700000000000)
+	to := time.UnixMilli(1700003600000)
+	query := `{"query":{"rules":[` +
+		`{"field":"entry_time","operator":"between","type":"date","value":["1700000000000","1700003600000"]},` +
+		`{"field":"severity","operator":"in","type":"string","value":["Critical","Major"]}` +
+		`],"condition":"AND"}}`
+
+	gock.New(testURL).Post("/dataservice/alarms").BodyString(query).Reply(200).
+		BodyString(`{"data":[{"uuid":"A1"}],"pageInfo":{"hasMoreData":true,"scrollId":"S1"}}`)
+	gock.New(testURL).Post("/dataservice/alarms/page").MatchParam("scrollId", "S1").BodyString(query).Reply(200).
+		BodyString(`{"data":[{"uuid":"A2"}],"pageInfo":{"hasMoreData":false}}`)
+	alarms, err := client.GetAlarms([]string{"Critical", "Major"}, from, to)
+	assert.NoError(t, err)
+	assert.Len(t, alarms, 2)
+	assert.Equal(t, "A2", alarms[1].Get("uuid").String())
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientAcknowledgeAlarms tests the Client::AcknowledgeAlarms method.
+func TestClientAcknowledgeAlarms(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Success
+	gock.New(testURL).Post("/dataservice/alarms/markviewed").BodyString(`{"alarm_uuid":["A1"]}`).Reply(200)
+	gock.New(testURL).Post("/dataservice/alarms/markviewed").BodyString(`{"alarm_uuid":["A2"]}`).Reply(200)
+	assert.NoError(t, client.AcknowledgeAlarms([]string{"A1", "A2"}))
+
+	// Per alarm failure
+	gock.New(testURL).Post("/dataservice/alarms/markviewed").BodyString(`{"alarm_uuid":["A1"]}`).Reply(200)
+	gock.New(testURL).Post("/dataservice/alarms/markviewed").BodyString(`{"alarm_uuid":["A2"]}`).Reply(400)
+	err := client.AcknowledgeAlarms([]string{"A1", "A2"})
+	assert.ErrorContains(t, err, "1 of 2 alarms not acknowledged (A2)")
+	assert.True(t, gock.IsDone())
+
+	// Invalid UUIDs
+	assert.Error(t, client.AcknowledgeAlarms(nil))
+	assert.Error(t, client.AcknowledgeAlarms([]string{"A1", " "}))
+	assert.Error(t, client.AcknowledgeAlarms([]string{"A1", "A1"}))
+}
+
+// TestClientDismissAlarms tests the Client::DismissAlarms method.
+func TestClientDismissAlarms(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Post("/dataservice/alarms/clear").BodyString(`{"alarm_uuid":["A1"]}`).Reply(200)
+	gock.New(testURL).Post("/dataservice/alarms/clear").BodyString(`{"alarm_uuid":["A2"]}`).ReplyError(errors.New("connection reset"))
+	err := client.DismissAlarms([]string{"A1", "A2"}, Retries(0))
+	assert.ErrorContains(t, err, "1 of 2 alarms not dismissed (A2)")
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,69 @@
+package sdwan
+
+import (
+	"sort"
+	"time"
+)
+
+// AuditEntry is a single entry of the vManage audit log.
+type AuditEntry struct {
+	// ID is the ID of the entry (logid).
+	ID string
+	// Time is the time the action has been performed (entry_time).
+	Time time.Time
+	// User is the user who performed the action (loguser).
+	User string
+	// SourceIP is the IP address the user connected from (logusersrcip).
+	SourceIP string
+	// Resource is the affected module, e.g. "template" or "device" (logmodule).
+	Resource string
+	// Action is the performed action, e.g. "update" or "login" (logfeature).
+	Action string
+	// Message is the description of the action (logmessage).
+	Message string
+	// DeviceID is the ID of the affected device if any (logdeviceid).
+	DeviceID string
+	// Tenant is the tenant the action has been performed for.
+	Tenant string
+	// Res is the complete entry.
+	Res Res
+}
+
+// ParseAuditEntry parses an entry of an audit log response.
+func ParseAuditEntry(res Res) AuditEntry {
+	return AuditEntry{
+		ID:       res.Get("logid").String(),
+		Time:     time.UnixMilli(res.Get("entry_time").Int()),
+		User:     res.Get("loguser").String(),
+		SourceIP: res.Get("logusersrcip").String(),
+		Resource: res.Get("logmodule").String(),
+		Action:   res.Get("logfeature").String(),
+		Message:  res.Get("logmessage").String(),
+		DeviceID: res.Get("logdeviceid").String(),
+		Tenant:   res.Get("tenant").String(),
+		Res:      res,
+	}
+}
+
+// GetAuditLogs retrieves all audit log entries between two points in time. The filters limit the entries to
+// the given values of audit log fields, e.g. {"loguser": "admin", "logmodule": "template"}.
+// Pagination is handled transparently by following the scrollId of each page.
+//
+//	entries, err := client.GetAuditLogs(time.Now().Add(-24*time.Hour), time.Now(), map[string]string{"loguser": "admin"})
+func (client *Client) GetAuditLogs(from, to time.Time, filters map[string]string, mods ...func(*Req)) ([]AuditEntry, error) {
+	q := StatisticsQuery{}.Rule("entry_time", "between", "date", epochMillis(from), epochMillis(to))
+	fields := make([]string, 0, len(filters))
+	for field := range filters {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	for _, field := range fields {
+		q = q.Rule(field, "in", "string", filters[field])
+	}
+	results, err := client.scrollQuery("/auditlog", q.Body().Str, mods...)
+	entries := make([]AuditEntry, len(results))
+	for i, result := range results {
+		entries[i] = ParseAuditEntry(result)
+	}
+	return entries, err
+}
@@ -0,0 +1,37 @@
+package sdwan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientGetAuditLogs tests the Client::GetAuditLogs method.
+func TestClientGetAuditLogs(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	from := time.UnixMilli(1700000000000)
+	to := time.UnixMilli(1700003600000)
+	query := `{"query":{"rules":[` +
+		`{"field":"entry_time","operator":"between","type":"date","value":["1700000000000","1700003600000"]},` +
+		`{"field":"logmodule","operator":"in","type":"string","value":["template"]},` +
+		`{"field":"loguser","operator":"in","type":"string","value":["admin"]}` +
+		`],"condition":"AND"}}`
+
+	gock.New(testURL).Post("/dataservice/auditlog").BodyString(query).Reply(200).
+		BodyString(`{"data":[{"logid":"L1","entry_time":1700000001000,"loguser":"admin","logmodule":"template","logfeature":"update","tenant":"t1"}],"pageInfo":{"hasMoreData":true,"scrollId":"S1"}}`)
+	gock.New(testURL).Post("/dataservice/auditlog/page").MatchParam("scrollId", "S1").BodyString(query).Reply(200).
+		BodyString(`{"data":[{"logid":"L2"}],"pageInfo":{"hasMoreData":false}}`)
+	entries, err := client.GetAuditLogs(from, to, map[string]string{"logmodule": "template", "loguser": "admin"})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, "admin", entries[0].User)
+	assert.Equal(t, "update", entries[0].Action)
+	assert.Equal(t, "template", entries[0].Resource)
+	assert.Equal(t, "t1", entries[0].Tenant)
+	assert.True(t, entries[0].Time.Equal(time.UnixMilli(1700000001000)))
+	assert.Equal(t, "L2", entries[1].ID)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,26 @@
+package sdwan
+
+import (
+	"time"
+)
+
+// PushCertificates sends the WAN edge certificate list (serial numbers and validity states) to all controllers
+// and waits for the resulting task to complete. The ID of the task is returned.
+func (client *Client) PushCertificates(timeout time.Duration, mods ...func(*Req)) (string, error) {
+	res, err := client.Post("/certificate/vedge/list?action=push", "{}", mods...)
+	if err != nil {
+		return "", err
+	}
+	taskID := res.Get("id").String()
+	if _, err := client.WaitForTask(taskID, timeout, mods...); err != nil {
+		return taskID, err
+	}
+	return taskID, nil
+}
+
+// InstallRootCert installs a PEM encoded enterprise root CA certificate, which vManage distributes to all devices.
+func (client *Client) InstallRootCert(pem string, mods ...func(*Req)) error {
+	body := Body{}.Set("enterpriseRootCA", pem)
+	_, err := client.Put("/settings/configuration/certificate/enterpriseRootCA", body.Str, mods...)
+	return err
+}
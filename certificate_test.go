@@ -0,0 +1,37 @@
+package sdwan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientPushCertificates tests the Client::PushCertificates method.
+func TestClientPushCertificates(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	withClock(&fakeClock{now: time.Now()})(&client)
+
+	gock.New(testURL).Post("/dataservice/certificate/vedge/list").MatchParam("action", "push").Reply(200).BodyString(`{"id":"T1"}`)
+	gock.New(testURL).Get("/dataservice/device/action/status/T1").Reply(200).BodyString(`{"summary":{"status":"done","count":{"Success":2}}}`)
+	taskID, err := client.PushCertificates(time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "T1", taskID)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientInstallRootCert tests the Client::InstallRootCert method.
+func TestClientInstallRootCert(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).
+		Put("/dataservice/settings/configuration/certificate/enterpriseRootCA").
+		BodyString(`{"enterpriseRootCA":"-----BEGIN CERTIFICATE-----\nABC\n-----END CERTIFICATE-----"}`).
+		Reply(200)
+	err := client.InstallRootCert("-----BEGIN CERTIFICATE-----\nABC\n-----END CERTIFICATE-----")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
@@ -3,26 +3,52 @@ package sdwan
 
 import (
 	"bytes"
+	"compress/gzip"
+	"context"
+	cryptorand "crypto/rand"
 	"crypto/tls"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"log"
 	"math"
 	"math/rand"
+	"net"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/tidwall/gjson"
+	"golang.org/x/sync/singleflight"
 )
 
 const DefaultMaxRetries int = 3
 const DefaultBackoffMinDelay int = 2
 const DefaultBackoffMaxDelay int = 60
 const DefaultBackoffDelayFactor float64 = 3
+const DefaultLoginMaxRetries int = 3
+const DefaultLoginBackoffMinDelay int = 5
+const DefaultLoginBackoffMaxDelay int = 60
+const DefaultLoginBackoffDelayFactor float64 = 3
+const DefaultAuthLoginPath string = "/j_security_check"
+const DefaultAuthTokenPath string = "/dataservice/client/token"
+const DefaultLockWaitTimeout int = 600
+const DefaultRetryAfterJitter float64 = 0.2
+const TokenCookieName string = "XSRF-TOKEN"
+
+// Reasons logged for retried requests.
+const (
+	RetryReasonConnectionError = "connection_error"
+	RetryReasonReadError       = "read_error"
+	RetryReasonRateLimited     = "rate_limited"
+	RetryReasonServerError     = "server_error"
+)
 
 // Client is an HTTP SDWAN client.
 // Use sdwan.NewClient to initiate a client.
@@ -32,6 +58,8 @@ type Client struct {
 	HttpClient *http.Client
 	// Url is the SDWAN vManage IP or hostname, e.g. https://10.0.0.1:443 (port is optional).
 	Url string
+	// Port overrides the port of Url if not 0, see Port.
+	Port int
 	// Token is the current authentication token
 	Token string
 	// Usr is the SDWAN username.
@@ -48,14 +76,114 @@ type Client struct {
 	BackoffMaxDelay int
 	// Backoff delay factor
 	BackoffDelayFactor float64
+	// Maximum number of login retries
+	LoginMaxRetries int
+	// Minimum delay between two login retries
+	LoginBackoffMinDelay int
+	// Maximum delay between two login retries
+	LoginBackoffMaxDelay int
+	// Login backoff delay factor
+	LoginBackoffDelayFactor float64
 	// Authentication mutex
 	AuthenticationMutex *sync.Mutex
+	// ExternalAuth disables the username/password login, the session cookie is provided using SetCookie.
+	ExternalAuth bool
+	// AuthLoginPath is the path used to submit the credentials.
+	AuthLoginPath string
+	// AuthTokenPath is the path used to retrieve the XSRF token.
+	AuthTokenPath string
+	// SignRequest is invoked right before a request is sent, e.g. to add a signature required by an API gateway.
+	SignRequest func(*http.Request) error
+	// CorrelationIDGenerator generates the correlation IDs of requests, random UUIDs are used if not set.
+	CorrelationIDGenerator func() string
+	// CancelTaskOnAbort indicates whether WaitForTaskContext cancels the task once its context is canceled.
+	CancelTaskOnAbort bool
+	// TokenAsCookie indicates whether the XSRF token is sent as cookie in addition to the header.
+	TokenAsCookie bool
+	// AuthExpiryDetector determines whether a response indicates an expired session, see DefaultAuthExpiryDetector.
+	AuthExpiryDetector func(res Res, statusCode int) bool
+	// BeforeAttempt is invoked before each attempt of a request, e.g. to refresh time-sensitive headers.
+	BeforeAttempt func(req *http.Request, attempt int)
+	// URLRewrite is invoked for every request built by NewReq to modify the request URL.
+	URLRewrite func(u *url.URL)
+	// PrettyLog determines if logged JSON payloads are indented.
+	PrettyLog bool
+	// Hosts are the URLs of all vManage nodes used for failover, Url is the currently active one.
+	Hosts []string
+	// urlMutex guards Url against concurrent failovers, writers also hold the AuthenticationMutex
+	urlMutex *sync.RWMutex
+	// Maximum time in seconds to wait for a configuration lock held by another operation (see WaitForLock)
+	LockWaitTimeout int
+	// Random jitter fraction applied to the delay of rate limited requests, 0 disables the jitter
+	RetryAfterJitter float64
+	// Maximum number of requests in flight at the same time, 0 means unlimited
+	MaxConcurrentRequests int
+	// requestSlots limits the number of requests in flight if MaxConcurrentRequests is set
+	requestSlots *prioritySemaphore
+	// HARFile is the path of the HTTP Archive file all requests are recorded to, see RecordHAR
+	HARFile string
+	// harRecorder records requests if HARFile is set
+	harRecorder *harRecorder
+	// Number of recent requests kept in memory for DumpRecentRequests, 0 disables the recording
+	RecentRequests int
+	// recentRequests keeps the most recent requests if RecentRequests is set
+	recentRequests *requestRing
+	// Singleflight determines if identical concurrent GET requests share a single round trip
+	Singleflight bool
+	// inflight coalesces identical GET requests if Singleflight is enabled
+	inflight *singleflight.Group
+	// lifecycle tracks requests in flight for Shutdown
+	lifecycle *lifecycle
+	// templateCache maps template names to IDs
+	templateCache *templateCache
+	// tenantSessions holds the session IDs used for requests on behalf of tenants
+	tenantSessions *tenantSessions
+	// tokenExpiry is the expiry time of the current session, zero if unknown
+	tokenExpiry time.Time
+	// tokenIssued is the time the current token has been retrieved, zero if none
+	tokenIssued time.Time
+	// logins is the number of successful logins
+	logins int
+	// reauths is the number of authentications replacing a previous session
+	reauths int
+	// session is shared with the other clients of a ClientPool, nil otherwise
+	session *poolSession
+	// now returns the current time, time.Now if nil (replaced by tests)
+	now func() time.Time
+	// sleep pauses the current goroutine, time.Sleep if nil (replaced by tests)
+	sleep func(time.Duration)
+}
+
+// defaultOptions are the modifiers applied to every new client, see SetDefaultClientOptions.
+var defaultOptions struct {
+	mutex sync.RWMutex
+	mods  []func(*Client)
+}
+
+// SetDefaultClientOptions sets modifiers which are applied to every client created by NewClient afterwards,
+// e.g. to share the same timeouts and retry settings across an application. The default options are applied
+// before the modifiers passed to NewClient, which therefore take precedence. Each call replaces the previous
+// default options, calling it without modifiers removes them. It is safe to call concurrently with NewClient,
+// clients created before are not changed.
+func SetDefaultClientOptions(mods ...func(*Client)) {
+	defaultOptions.mutex.Lock()
+	defer defaultOptions.mutex.Unlock()
+	defaultOptions.mods = append([]func(*Client){}, mods...)
+}
+
+// defaultClientOptions returns the modifiers set by SetDefaultClientOptions.
+func defaultClientOptions() []func(*Client) {
+	defaultOptions.mutex.RLock()
+	defer defaultOptions.mutex.RUnlock()
+	return defaultOptions.mods
 }
 
 // NewClient creates a new SDWAN HTTP client.
 // Pass modifiers in to modify the behavior of the client, e.g.
 //
 //	client, _ := NewClient("vmanage1.cisco.com", "user", "password", true, RequestTimeout(120))
+//
+// Modifiers set using SetDefaultClientOptions are applied before the ones passed to NewClient.
 func NewClient(url, usr, pwd string, insecure bool, mods ...func(*Client)) (Client, error) {
 	tr := &http.Transport{
 		TLSClientConfig: &tls.Config{InsecureSkipVerify: insecure},
@@ -69,21 +197,62 @@ func NewClient(url, usr, pwd string, insecure bool, mods ...func(*Client)) (Clie
 	}
 
 	client := Client{
-		HttpClient:          &httpClient,
-		Url:                 url,
-		Usr:                 usr,
-		Pwd:                 pwd,
-		Insecure:            insecure,
-		MaxRetries:          DefaultMaxRetries,
-		BackoffMinDelay:     DefaultBackoffMinDelay,
-		BackoffMaxDelay:     DefaultBackoffMaxDelay,
-		BackoffDelayFactor:  DefaultBackoffDelayFactor,
-		AuthenticationMutex: &sync.Mutex{},
+		HttpClient:              &httpClient,
+		Url:                     withScheme(url),
+		Usr:                     usr,
+		Pwd:                     pwd,
+		Insecure:                insecure,
+		MaxRetries:              DefaultMaxRetries,
+		BackoffMinDelay:         DefaultBackoffMinDelay,
+		BackoffMaxDelay:         DefaultBackoffMaxDelay,
+		BackoffDelayFactor:      DefaultBackoffDelayFactor,
+		LoginMaxRetries:         DefaultLoginMaxRetries,
+		LoginBackoffMinDelay:    DefaultLoginBackoffMinDelay,
+		LoginBackoffMaxDelay:    DefaultLoginBackoffMaxDelay,
+		LoginBackoffDelayFactor: DefaultLoginBackoffDelayFactor,
+		AuthenticationMutex:     &sync.Mutex{},
+		urlMutex:                &sync.RWMutex{},
+		AuthLoginPath:           DefaultAuthLoginPath,
+		AuthTokenPath:           DefaultAuthTokenPath,
+		LockWaitTimeout:         DefaultLockWaitTimeout,
+		AuthExpiryDetector:      DefaultAuthExpiryDetector,
+		RetryAfterJitter:        DefaultRetryAfterJitter,
+		templateCache:           newTemplateCache(),
+		tenantSessions:          &tenantSessions{},
+		inflight:                &singleflight.Group{},
+		lifecycle:               &lifecycle{},
 	}
 
+	for _, mod := range defaultClientOptions() {
+		mod(&client)
+	}
 	for _, mod := range mods {
 		mod(&client)
 	}
+	if client.HttpClient.Jar == nil {
+		return client, fmt.Errorf("cookie jar must not be nil")
+	}
+	if client.Port != 0 {
+		u, err := withPort(client.Url, client.Port)
+		if err != nil {
+			return client, err
+		}
+		for i, host := range client.Hosts {
+			if client.Hosts[i], err = withPort(withScheme(host), client.Port); err != nil {
+				return client, err
+			}
+		}
+		client.Url = u
+	}
+	if client.MaxConcurrentRequests > 0 {
+		client.requestSlots = newPrioritySemaphore(client.MaxConcurrentRequests)
+	}
+	if client.HARFile != "" {
+		client.harRecorder = &harRecorder{path: client.HARFile}
+	}
+	if client.RecentRequests > 0 {
+		client.recentRequests = newRequestRing(client.RecentRequests)
+	}
 	return client, nil
 }
 
@@ -94,10 +263,20 @@ func RequestTimeout(x time.Duration) func(*Client) {
 	}
 }
 
+// CookieJar replaces the internal cookie jar, e.g. to share a session between multiple clients
+// or to persist the session cookies. NewClient returns an error if the jar is nil.
+func CookieJar(jar http.CookieJar) func(*Client) {
+	return func(client *Client) {
+		client.HttpClient.Jar = jar
+	}
+}
+
 // MaxRetries modifies the maximum number of retries from the default of 3.
+// A value of 0 means exactly one attempt without any retries, regardless of the failure
+// (connection errors, server errors or rate limiting). Negative values are treated as 0.
 func MaxRetries(x int) func(*Client) {
 	return func(client *Client) {
-		client.MaxRetries = x
+		client.MaxRetries = nonNegative(x)
 	}
 }
 
@@ -122,16 +301,248 @@ func BackoffDelayFactor(x float64) func(*Client) {
 	}
 }
 
+// LoginMaxRetries modifies the maximum number of login retries from the default of 3.
+// A value of 0 means exactly one login attempt, negative values are treated as 0.
+func LoginMaxRetries(x int) func(*Client) {
+	return func(client *Client) {
+		client.LoginMaxRetries = nonNegative(x)
+	}
+}
+
+// LoginBackoffMinDelay modifies the minimum delay between two login retries from the default of 5.
+func LoginBackoffMinDelay(x int) func(*Client) {
+	return func(client *Client) {
+		client.LoginBackoffMinDelay = x
+	}
+}
+
+// LoginBackoffMaxDelay modifies the maximum delay between two login retries from the default of 60.
+func LoginBackoffMaxDelay(x int) func(*Client) {
+	return func(client *Client) {
+		client.LoginBackoffMaxDelay = x
+	}
+}
+
+// LoginBackoffDelayFactor modifies the login backoff delay factor from the default of 3.
+func LoginBackoffDelayFactor(x float64) func(*Client) {
+	return func(client *Client) {
+		client.LoginBackoffDelayFactor = x
+	}
+}
+
+// ExternalAuth disables the username/password login in favor of an externally established session, e.g. SSO.
+// The session cookie must be provided using SetCookie.
+func ExternalAuth(x bool) func(*Client) {
+	return func(client *Client) {
+		client.ExternalAuth = x
+	}
+}
+
+// AuthLoginPath modifies the path used to submit the credentials from the default of "/j_security_check".
+func AuthLoginPath(x string) func(*Client) {
+	return func(client *Client) {
+		client.AuthLoginPath = x
+	}
+}
+
+// AuthTokenPath modifies the path used to retrieve the XSRF token from the default of "/dataservice/client/token".
+func AuthTokenPath(x string) func(*Client) {
+	return func(client *Client) {
+		client.AuthTokenPath = x
+	}
+}
+
+// CorrelationIDGenerator replaces the generation of correlation IDs, e.g. to create predictable IDs in tests.
+// Each call of Do is assigned a correlation ID which is included in all log records of the request and its retries.
+func CorrelationIDGenerator(x func() string) func(*Client) {
+	return func(client *Client) {
+		client.CorrelationIDGenerator = x
+	}
+}
+
+// Singleflight coalesces identical GET requests in flight at the same time, e.g. reference data requested by
+// many goroutines during a parallel reconciliation, into a single round trip. Requests are identical if
+// they have the same URL (including the query), Accept header and tenant (see OnBehalfOfTenant), all callers
+// receive the same response. The default is false. As the request of the first caller is sent, its context and
+// modifiers apply to all callers.
+func Singleflight(x bool) func(*Client) {
+	return func(client *Client) {
+		client.Singleflight = x
+	}
+}
+
+// CancelTaskOnAbort cancels the vManage task if WaitForTaskContext stops waiting because its context is canceled.
+func CancelTaskOnAbort(x bool) func(*Client) {
+	return func(client *Client) {
+		client.CancelTaskOnAbort = x
+	}
+}
+
+// TokenAsCookie sends the XSRF token as "XSRF-TOKEN" cookie in addition to the "X-XSRF-TOKEN" header.
+// Some vManage releases reject requests with a 403 status code if the token is only sent as header.
+func TokenAsCookie(x bool) func(*Client) {
+	return func(client *Client) {
+		client.TokenAsCookie = x
+	}
+}
+
+// AuthExpiryDetector replaces the detection of expired sessions (see DefaultAuthExpiryDetector), e.g. to adapt
+// to the behavior of a specific vManage version. If a response indicates an expired session, the client
+// logs in again and repeats the request once.
+func AuthExpiryDetector(x func(res Res, statusCode int) bool) func(*Client) {
+	return func(client *Client) {
+		client.AuthExpiryDetector = x
+	}
+}
+
+// DefaultAuthExpiryDetector detects an expired session by the HTTP status codes 401 and 403
+// or a successful response containing the HTML login page, depending on the vManage version and endpoint.
+func DefaultAuthExpiryDetector(res Res, statusCode int) bool {
+	if statusCode == 401 || statusCode == 403 {
+		return true
+	}
+	// other HTML pages, e.g. because of a wrong path, are not treated as login page
+	return statusCode >= 200 && statusCode <= 299 && isLoginPage(res.Bytes())
+}
+
+// BeforeAttempt sets a hook invoked before each attempt of a request sent with Do, the first attempt is 0.
+// The hook runs on every retry, e.g. to set a nonce or timestamp header required by an API gateway.
+// It is invoked after the request body has been reset and before the SignRequest hook.
+func BeforeAttempt(x func(req *http.Request, attempt int)) func(*Client) {
+	return func(client *Client) {
+		client.BeforeAttempt = x
+	}
+}
+
+// URLRewrite sets a hook invoked by NewReq after the request URL has been built (including the request modifiers),
+// e.g. to inject a tenant segment into every path. It applies to all requests including login requests.
+func URLRewrite(x func(u *url.URL)) func(*Client) {
+	return func(client *Client) {
+		client.URLRewrite = x
+	}
+}
+
+// SignRequest sets a hook invoked right before each request (including retries and login requests) is sent.
+// The request body can be read by the hook and is restored afterwards, e.g. to calculate an HMAC signature.
+func SignRequest(x func(*http.Request) error) func(*Client) {
+	return func(client *Client) {
+		client.SignRequest = x
+	}
+}
+
+// PrettyLog enables indentation of logged JSON payloads, the payloads sent to vManage are not modified.
+func PrettyLog(x bool) func(*Client) {
+	return func(client *Client) {
+		client.PrettyLog = x
+	}
+}
+
+// Hosts sets the URLs of multiple vManage nodes, e.g. []string{"https://10.0.0.1", "https://10.0.0.2"}.
+// If a request fails with a connection error or a server error, the client fails over to the next node
+// and authenticates against it. Logins and token retrievals fail over the same way. The node is used for all
+// subsequent requests until it fails itself.
+// The URL passed to NewClient is added to the list if not already present.
+func Hosts(x []string) func(*Client) {
+	return func(client *Client) {
+		client.Hosts = append([]string(nil), x...)
+		for _, host := range x {
+			if host == client.Url {
+				return
+			}
+		}
+		client.Hosts = append([]string{client.Url}, x...)
+	}
+}
+
+// Port sets the port of the vManage URL, e.g. if host and port are configured separately.
+// It replaces a port contained in the URL passed to NewClient and in the URLs of Hosts, NewClient returns an
+// error if the port is not between 1 and 65535.
+//
+//	client, _ := NewClient("vmanage1.cisco.com", "user", "password", true, Port(8443))
+func Port(x int) func(*Client) {
+	return func(client *Client) {
+		client.Port = x
+	}
+}
+
+// withScheme adds the https scheme to a URL without a scheme, e.g. "vmanage1.cisco.com".
+func withScheme(rawURL string) string {
+	if rawURL != "" && !strings.Contains(rawURL, "://") {
+		return "https://" + rawURL
+	}
+	return rawURL
+}
+
+// withPort replaces the port of a URL.
+func withPort(rawURL string, port int) (string, error) {
+	if port < 1 || port > 65535 {
+		return rawURL, fmt.Errorf("invalid port: %d", port)
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL, err
+	}
+	u.Host = net.JoinHostPort(u.Hostname(), strconv.Itoa(port))
+	return u.String(), nil
+}
+
+// LockWaitTimeout modifies the maximum time in seconds to wait for a configuration lock from the default of 600.
+func LockWaitTimeout(x int) func(*Client) {
+	return func(client *Client) {
+		client.LockWaitTimeout = x
+	}
+}
+
+// RetryAfterJitter modifies the random jitter fraction applied to the delay of rate limited requests from the default of 0.2,
+// e.g. a Retry-After of 10 seconds results in a delay between 8 and 12 seconds. This prevents concurrent requests
+// from being retried at the same time. A value of 0 disables the jitter.
+func RetryAfterJitter(x float64) func(*Client) {
+	return func(client *Client) {
+		client.RetryAfterJitter = x
+	}
+}
+
+// MaxConcurrentRequests limits the number of requests in flight at the same time, e.g. to avoid exhausting the
+// API worker threads of vManage. Further requests block until a request completes or their context is canceled.
+// Blocked requests acquire a slot by descending priority (see Priority).
+func MaxConcurrentRequests(x int) func(*Client) {
+	return func(client *Client) {
+		client.MaxConcurrentRequests = x
+	}
+}
+
+// RecordHAR records all requests and responses to an HTTP Archive (HAR 1.2) file, e.g. to share a session with Cisco TAC.
+// Credentials, tokens and cookies are redacted. The file is written when the client is closed (see Close).
+func RecordHAR(path string) func(*Client) {
+	return func(client *Client) {
+		client.HARFile = path
+	}
+}
+
+// RequestRecorder keeps the given number of most recent requests and responses in memory, which can be written
+// using DumpRecentRequests when an unexpected error occurs. Unlike debug logging, nothing is written unless
+// requested. Sensitive headers and the payloads of requests with LogPayload disabled are redacted.
+func RequestRecorder(size int) func(*Client) {
+	return func(client *Client) {
+		client.RecentRequests = size
+	}
+}
+
 // NewReq creates a new Req request for this client.
-func (client Client) NewReq(method, uri string, body io.Reader, mods ...func(*Req)) Req {
-	httpReq, _ := http.NewRequest(method, client.Url+uri, body)
+func (client *Client) NewReq(method, uri string, body io.Reader, mods ...func(*Req)) Req {
+	httpReq, _ := http.NewRequest(method, client.currentURL()+uri, body)
 	req := Req{
 		HttpReq:    httpReq,
 		LogPayload: true,
+		MaxRetries: client.MaxRetries,
 	}
 	for _, mod := range mods {
 		mod(&req)
 	}
+	if client.URLRewrite != nil && httpReq != nil {
+		client.URLRewrite(httpReq.URL)
+		httpReq.Host = httpReq.URL.Host
+	}
 	return req
 }
 
@@ -140,89 +551,280 @@ func (client Client) NewReq(method, uri string, body io.Reader, mods ...func(*Re
 //
 //	req := client.NewReq("GET", "/admin/resourcegroup", nil)
 //	res, _ := client.Do(req)
+//
+// Each call is assigned a correlation ID, which is included in all log records of the request and its retries
+// and returned as part of the response (see Res.CorrelationID).
 func (client *Client) Do(req Req) (Res, error) {
+	if req.CorrelationID == "" {
+		req.CorrelationID = client.newCorrelationID()
+	}
+	var res Res
+	var err error
+	if client.Singleflight && client.inflight != nil && req.HttpReq.Method == "GET" {
+		key := req.HttpReq.URL.String() + "\n" + req.HttpReq.Header.Get("Accept") + "\n" + req.Tenant + "\n" + strconv.FormatBool(req.IgnoreJSONError)
+		var v interface{}
+		var shared bool
+		v, err, shared = client.inflight.Do(key, func() (interface{}, error) {
+			return client.do(req)
+		})
+		res = v.(Res)
+		if shared {
+			req.logf("[DEBUG] Shared response of identical request in flight: GET, %s", req.HttpReq.URL)
+		}
+	} else {
+		res, err = client.do(req)
+	}
+	res.CorrelationID = req.CorrelationID
+	return res, err
+}
+
+// do makes a request, see Do.
+func (client *Client) do(req Req) (Res, error) {
+	if err := client.beginRequest(); err != nil {
+		return Res{}, err
+	}
+	defer client.endRequest()
 	// add token
-	req.HttpReq.Header.Add("X-XSRF-TOKEN", client.Token)
-	// retain the request body across multiple attempts
-	var body []byte
+	var tokenCookie *http.Cookie
+	if !req.NoAuth {
+		req.HttpReq.Header.Add("X-XSRF-TOKEN", client.Token)
+		if client.TokenAsCookie && !hasCookie(req.Cookies, TokenCookieName) {
+			tokenCookie = &http.Cookie{Name: TokenCookieName, Value: client.Token}
+			req.Cookies = append(req.Cookies, tokenCookie)
+			req.HttpReq.AddCookie(tokenCookie)
+		}
+		if req.Tenant != "" {
+			id, err := client.tenantSessionID(req.Tenant)
+			if err != nil {
+				return Res{}, err
+			}
+			req.HttpReq.Header.Set(TenantSessionHeader, id)
+		}
+	}
+	// requests sent to another cluster member (see Node) reuse the session cookies of the client URL
+	if u, err := url.Parse(client.currentURL()); err == nil && client.HttpClient.Jar != nil && u.Host != req.HttpReq.URL.Host {
+		for _, cookie := range client.HttpClient.Jar.Cookies(u) {
+			if !hasCookie(req.Cookies, cookie.Name) {
+				req.HttpReq.AddCookie(cookie)
+			}
+		}
+	}
+	// cookies of the request take precedence over cookies of the jar with the same name
+	httpClient := client.HttpClient
+	if len(req.Cookies) > 0 && httpClient.Jar != nil {
+		c := *httpClient
+		c.Jar = requestCookieJar{CookieJar: httpClient.Jar, cookies: req.Cookies}
+		httpClient = &c
+	}
+	// retain the request body across multiple attempts, payload is the uncompressed body used for logging
+	var payload []byte
 	if req.HttpReq.Body != nil {
-		body, _ = io.ReadAll(req.HttpReq.Body)
+		payload, _ = readAll(req.HttpReq.Body)
+		if req.BodyTransform != nil {
+			transformed, err := req.BodyTransform(payload)
+			if err != nil {
+				req.logf("[ERROR] HTTP Request body transformation failed: %+v", err)
+				return Res{}, err
+			}
+			payload = transformed
+		}
+	}
+	body := payload
+	if req.Gzip && len(payload) > 0 {
+		// compress once, retries send the same compressed bytes
+		compressed, err := gzipBody(payload)
+		if err != nil {
+			return Res{}, err
+		}
+		body = compressed
+		req.HttpReq.Header.Set("Content-Encoding", "gzip")
 	}
+	setBody(req.HttpReq, body)
 
 	var res Res
+	failovers := 0
+	lockRetries := 0
+	gzipFallbacks := 0
+	reauths := 0
+	var lockWaitStart time.Time
 
 	for attempts := 0; ; attempts++ {
-		req.HttpReq.Body = io.NopCloser(bytes.NewBuffer(body))
+		// failovers to another host, waiting for a configuration lock, falling back to
+		// an uncompressed body and logging in again are not counted as retries
+		retries := attempts - failovers - lockRetries - gzipFallbacks - reauths
+		if err := contextError(req); err != nil {
+			req.logf("[DEBUG] Exit from Do method")
+			return res, err
+		}
+		if len(body) > 0 {
+			req.HttpReq.Body = io.NopCloser(bytes.NewReader(body))
+		} else {
+			req.HttpReq.Body = http.NoBody
+		}
 		if req.LogPayload {
-			log.Printf("[DEBUG] HTTP Request: %s, %s, %s", req.HttpReq.Method, req.HttpReq.URL, req.HttpReq.Body)
+			req.logf("[DEBUG] HTTP Request: %s, %s, %s", req.HttpReq.Method, req.HttpReq.URL, client.formatPayload(payload))
 		} else {
-			log.Printf("[DEBUG] HTTP Request: %s, %s", req.HttpReq.Method, req.HttpReq.URL)
+			req.logf("[DEBUG] HTTP Request: %s, %s", req.HttpReq.Method, req.HttpReq.URL)
 		}
 
-		httpRes, err := client.HttpClient.Do(req.HttpReq)
+		if client.BeforeAttempt != nil {
+			client.BeforeAttempt(req.HttpReq, attempts)
+		}
+		if err := client.signRequest(req.HttpReq); err != nil {
+			req.logf("[ERROR] HTTP Request signing failed: %+v", err)
+			req.logf("[DEBUG] Exit from Do method")
+			return Res{}, err
+		}
+		if err := client.acquireRequestSlot(req.HttpReq.Context(), req.Priority); err != nil {
+			req.logf("[DEBUG] Exit from Do method")
+			return Res{}, err
+		}
+		started := client.clockNow()
+		httpRes, err := httpClient.Do(req.HttpReq)
 		if err != nil {
-			if ok := client.Backoff(attempts); !ok {
-				log.Printf("[ERROR] HTTP Connection error occured: %+v", err)
-				log.Printf("[DEBUG] Exit from Do method")
+			client.releaseRequestSlot()
+			client.recordFailure(req, payload, err, started)
+			if err := contextError(req); err != nil {
+				req.logf("[DEBUG] Exit from Do method")
 				return Res{}, err
-			} else {
-				log.Printf("[ERROR] HTTP Connection failed: %s, retries: %v", err, attempts)
+			}
+			req.logf("[ERROR] HTTP Connection failed: %s", err)
+			if client.failover(&req, failovers) {
+				failovers++
 				continue
 			}
+			if ok := client.retry(req, retries, RetryReasonConnectionError, 0, client.requestBackoffDelay(retries)); !ok {
+				req.logf("[DEBUG] Exit from Do method")
+				return Res{}, retriesExceededError{err}
+			}
+			continue
 		}
 
 		defer httpRes.Body.Close()
-		bodyBytes, err := io.ReadAll(httpRes.Body)
+		bodyBytes, err := readAll(httpRes.Body)
+		client.releaseRequestSlot()
 		if err != nil {
-			if ok := client.Backoff(attempts); !ok {
-				log.Printf("[ERROR] Cannot decode response body: %+v", err)
-				log.Printf("[DEBUG] Exit from Do method")
+			if err := contextError(req); err != nil {
+				req.logf("[DEBUG] Exit from Do method")
 				return Res{}, err
-			} else {
-				log.Printf("[ERROR] Cannot decode response body: %s, retries: %v", err, attempts)
-				continue
 			}
+			req.logf("[ERROR] Cannot decode response body: %s", err)
+			if ok := client.retry(req, retries, RetryReasonReadError, httpRes.StatusCode, client.requestBackoffDelay(retries)); !ok {
+				req.logf("[DEBUG] Exit from Do method")
+				return Res{}, retriesExceededError{err}
+			}
+			continue
+		}
+		client.recordExchange(req, payload, httpRes, bodyBytes, started)
+		if acceptsJSON(req.HttpReq) {
+			res = newRes(bodyBytes)
+		} else {
+			res = Res{body: bodyBytes}
 		}
-		res = Res(gjson.ParseBytes(bodyBytes))
+		res.Header = httpRes.Header
 		if req.LogPayload {
-			log.Printf("[DEBUG] HTTP Response: %s", res.Raw)
+			req.logf("[DEBUG] HTTP Response: %s", client.formatPayload(bodyBytes))
 		}
 
-		if httpRes.StatusCode >= 200 && httpRes.StatusCode <= 299 {
-			log.Printf("[DEBUG] Exit from Do method")
-			break
-		} else {
-			if ok := client.Backoff(attempts); !ok {
-				log.Printf("[ERROR] HTTP Request failed: StatusCode %v", httpRes.StatusCode)
-				log.Printf("[DEBUG] Exit from Do method")
-				return res, fmt.Errorf("HTTP Request failed: StatusCode %v", httpRes.StatusCode)
-			} else if httpRes.StatusCode == 429 {
-				retryAfter := httpRes.Header.Get("Retry-After")
-				retryAfterDuration := time.Duration(0)
-				if retryAfter == "0" {
-					retryAfterDuration = time.Second
-				} else if retryAfter != "" {
-					retryAfterDuration, _ = time.ParseDuration(retryAfter + "s")
-				} else {
-					retryAfterDuration = 15 * time.Second
+		if !req.NoAuth && reauths == 0 && client.authExpired(res, httpRes.StatusCode) {
+			req.logf("[WARNING] Session expired: StatusCode %v, authenticating again", httpRes.StatusCode)
+			if err := client.reauthenticate(req.HttpReq.Header.Get("X-XSRF-TOKEN")); err != nil {
+				req.logf("[DEBUG] Exit from Do method")
+				return res, err
+			}
+			req.HttpReq.Header.Set("X-XSRF-TOKEN", client.Token)
+			if tokenCookie != nil {
+				tokenCookie.Value = client.Token
+				replaceCookie(req.HttpReq, tokenCookie)
+			}
+			if req.Tenant != "" {
+				id, err := client.tenantSessionID(req.Tenant)
+				if err != nil {
+					req.logf("[DEBUG] Exit from Do method")
+					return res, err
 				}
-				log.Printf("[WARNING] HTTP Request rate limited, waiting %v seconds, Retries: %v", retryAfterDuration.Seconds(), attempts)
-				time.Sleep(retryAfterDuration)
+				req.HttpReq.Header.Set(TenantSessionHeader, id)
+			}
+			reauths++
+			continue
+		}
+
+		if httpRes.StatusCode == 415 && req.HttpReq.Header.Get("Content-Encoding") == "gzip" {
+			req.logf("[WARNING] Compressed request body not supported, sending uncompressed body")
+			req.HttpReq.Header.Del("Content-Encoding")
+			body = payload
+			setBody(req.HttpReq, body)
+			gzipFallbacks++
+			continue
+		}
+
+		if req.WaitForLock && isLockError(res) {
+			if lockWaitStart.IsZero() {
+				lockWaitStart = client.clockNow()
+			}
+			if client.waitForLock(req, lockWaitStart, lockRetries) {
+				lockRetries++
 				continue
-			} else if httpRes.StatusCode == 408 || (httpRes.StatusCode >= 500 && httpRes.StatusCode <= 599) {
-				log.Printf("[ERROR] HTTP Request failed: StatusCode %v, Retries: %v", httpRes.StatusCode, attempts)
+			}
+		}
+
+		if httpRes.StatusCode >= 200 && httpRes.StatusCode <= 299 {
+			res.Empty = len(bytes.TrimSpace(bodyBytes)) == 0
+			if req.ExpectJSON && !res.Empty && !isJSONContentType(httpRes.Header.Get("Content-Type")) {
+				req.logf("[ERROR] Unexpected response content type: %s", httpRes.Header.Get("Content-Type"))
+				req.logf("[DEBUG] Exit from Do method")
+				return res, fmt.Errorf("%w: %q, response: %s", ErrUnexpectedContentType, httpRes.Header.Get("Content-Type"), bodySnippet(bodyBytes))
+			}
+			req.logf("[DEBUG] Exit from Do method")
+			break
+		} else if httpRes.StatusCode == 429 {
+			req.logf("[WARNING] HTTP Request rate limited: StatusCode %v", httpRes.StatusCode)
+			retryAfterDuration, ok := parseRetryAfter(httpRes.Header.Get("Retry-After"), client.clockNow())
+			if !ok {
+				retryAfterDuration = 15 * time.Second
+			} else if retryAfterDuration == 0 {
+				retryAfterDuration = time.Second
+			}
+			retryAfterDuration = jitter(retryAfterDuration, client.RetryAfterJitter)
+			if ok := client.retry(req, retries, RetryReasonRateLimited, httpRes.StatusCode, retryAfterDuration); !ok {
+				req.logf("[DEBUG] Exit from Do method")
+				return res, retriesExceededError{fmt.Errorf("%w: StatusCode %v", ErrRateLimited, httpRes.StatusCode)}
+			}
+			continue
+		} else if httpRes.StatusCode == 408 || (httpRes.StatusCode >= 500 && httpRes.StatusCode <= 599) {
+			req.logf("[ERROR] HTTP Request failed: StatusCode %v", httpRes.StatusCode)
+			if client.failover(&req, failovers) {
+				failovers++
 				continue
-			} else {
-				log.Printf("[ERROR] HTTP Request failed: StatusCode %v", httpRes.StatusCode)
-				log.Printf("[DEBUG] Exit from Do method")
-				return res, fmt.Errorf("HTTP Request failed: StatusCode %v", httpRes.StatusCode)
 			}
+			delay := client.requestBackoffDelay(retries)
+			if httpRes.StatusCode == 503 || httpRes.StatusCode == 408 {
+				// vManage might announce the end of a maintenance window
+				if retryAfter, ok := parseRetryAfter(httpRes.Header.Get("Retry-After"), client.clockNow()); ok {
+					delay = jitter(retryAfter, client.RetryAfterJitter)
+					if maxDelay := time.Duration(client.BackoffMaxDelay) * time.Second; delay > maxDelay {
+						delay = maxDelay
+					}
+				}
+			}
+			if ok := client.retry(req, retries, RetryReasonServerError, httpRes.StatusCode, delay); !ok {
+				req.logf("[DEBUG] Exit from Do method")
+				return res, retriesExceededError{responseError(httpRes, bodyBytes)}
+			}
+			continue
+		} else {
+			req.logf("[ERROR] HTTP Request failed: StatusCode %v", httpRes.StatusCode)
+			req.logf("[DEBUG] Exit from Do method")
+			return res, responseError(httpRes, bodyBytes)
 		}
 	}
 
 	errCode := res.Get("error.code").Str
-	if errCode != "" {
-		log.Printf("[ERROR] JSON error: %s", res.Raw)
+	if errCode != "" && !req.IgnoreJSONError {
+		req.logf("[ERROR] JSON error: %s", res.Raw)
+		if details := res.errorDetailsString(); details != "" {
+			return res, fmt.Errorf("JSON error: %s, details: %s", res.Raw, details)
+		}
 		return res, fmt.Errorf("JSON error: %s", res.Raw)
 	}
 	return res, nil
@@ -232,19 +834,55 @@ func (client *Client) Do(req Req) (Res, error) {
 // Results will be the raw data structure as returned by vManage
 func (client *Client) Get(path string, mods ...func(*Req)) (Res, error) {
 	req := client.NewReq("GET", "/dataservice"+path, nil, mods...)
-	err := client.Authenticate()
-	if err != nil {
-		return Res{}, err
+	if !req.NoAuth {
+		err := client.Authenticate()
+		if err != nil {
+			return Res{}, err
+		}
 	}
 	return client.Do(req)
 }
 
+// GetData makes a GET request and returns the elements of the "data" array of the response.
+// An empty slice is returned if the "data" array is absent or empty.
+func (client *Client) GetData(path string, mods ...func(*Req)) ([]Res, error) {
+	res, err := client.Get(path, mods...)
+	if err != nil {
+		return []Res{}, err
+	}
+	return toResArray(res.Get("data").Array()), nil
+}
+
+// GetFiltered makes a GET request and returns the elements matching a GJSON query, e.g.
+//
+//	devices, _ := client.GetFiltered("/device", `data.#(reachability=="reachable")#`)
+//
+// The filtering is done client-side, the complete response is retrieved from vManage.
+// If the query matches a single value, a slice containing this value is returned.
+// An empty slice is returned if the query does not match.
+func (client *Client) GetFiltered(path, query string, mods ...func(*Req)) ([]Res, error) {
+	res, err := client.Get(path, mods...)
+	if err != nil {
+		return []Res{}, err
+	}
+	result := res.Get(query)
+	if result.IsArray() {
+		return toResArray(result.Array()), nil
+	}
+	if result.Exists() {
+		return []Res{toRes(result)}, nil
+	}
+	return []Res{}, nil
+}
+
 // Delete makes a DELETE request.
 func (client *Client) Delete(path string, mods ...func(*Req)) (Res, error) {
 	req := client.NewReq("DELETE", "/dataservice"+path, nil, mods...)
-	err := client.Authenticate()
-	if err != nil {
-		return Res{}, err
+	if !req.NoAuth {
+		err := client.Authenticate()
+		if err != nil {
+			return Res{}, err
+		}
 	}
 	return client.Do(req)
 }
@@ -253,20 +891,52 @@ func (client *Client) Delete(path string, mods ...func(*Req)) (Res, error) {
 // Hint: Use the Body struct to easily create DELETE body data.
 func (client *Client) DeleteBody(path, data string, mods ...func(*Req)) (Res, error) {
 	req := client.NewReq("DELETE", "/dataservice"+path, strings.NewReader(data), mods...)
-	err := client.Authenticate()
-	if err != nil {
-		return Res{}, err
+	if !req.NoAuth {
+		err := client.Authenticate()
+		if err != nil {
+			return Res{}, err
+		}
 	}
 	return client.Do(req)
 }
 
+// DeleteResult is the result of a single DELETE request made by DeleteMany.
+type DeleteResult struct {
+	// Path is the path of the deleted object.
+	Path string
+	// Success indicates whether the object has been deleted or did not exist.
+	Success bool
+	// Err is the error returned by the DELETE request.
+	Err error
+}
+
+// DeleteMany makes a DELETE request for each path.
+// If continueOnError is false, no further requests are made after the first failure.
+// Objects which do not exist (HTTP status code 404) are considered successfully deleted.
+func (client *Client) DeleteMany(paths []string, continueOnError bool, mods ...func(*Req)) []DeleteResult {
+	results := []DeleteResult{}
+	for _, path := range paths {
+		_, err := client.Delete(path, mods...)
+		if errors.Is(err, ErrNotFound) {
+			err = nil
+		}
+		results = append(results, DeleteResult{Path: path, Success: err == nil, Err: err})
+		if err != nil && !continueOnError {
+			break
+		}
+	}
+	return results
+}
+
 // Post makes a POST request and returns a GJSON result.
 // Hint: Use the Body struct to easily create POST body data.
 func (client *Client) Post(path, data string, mods ...func(*Req)) (Res, error) {
 	req := client.NewReq("POST", "/dataservice"+path, strings.NewReader(data), mods...)
-	err := client.Authenticate()
-	if err != nil {
-		return Res{}, err
+	if !req.NoAuth {
+		err := client.Authenticate()
+		if err != nil {
+			return Res{}, err
+		}
 	}
 	return client.Do(req)
 }
@@ -275,67 +945,586 @@ func (client *Client) Post(path, data string, mods ...func(*Req)) (Res, error) {
 // Hint: Use the Body struct to easily create PUT body data.
 func (client *Client) Put(path, data string, mods ...func(*Req)) (Res, error) {
 	req := client.NewReq("PUT", "/dataservice"+path, strings.NewReader(data), mods...)
-	err := client.Authenticate()
-	if err != nil {
-		return Res{}, err
+	if !req.NoAuth {
+		err := client.Authenticate()
+		if err != nil {
+			return Res{}, err
+		}
 	}
 	return client.Do(req)
 }
 
-// Login authenticates to the SDWAN vManage device.
-func (client *Client) Login() error {
-	data := url.Values{}
-	data.Set("j_username", client.Usr)
-	data.Set("j_password", client.Pwd)
-	for attempts := 0; ; attempts++ {
-		req := client.NewReq("POST", "/j_security_check", strings.NewReader(data.Encode()), NoLogPayload)
-		req.HttpReq.Header.Add("Content-Type", "application/x-www-form-urlencoded")
-		httpRes, err := client.HttpClient.Do(req.HttpReq)
-		if err != nil {
-			return err
-		}
-		if httpRes.StatusCode != 200 {
-			log.Printf("[ERROR] Authentication failed: StatusCode %v", httpRes.StatusCode)
-			return fmt.Errorf("authentication failed, status code: %v", httpRes.StatusCode)
-		}
-		defer httpRes.Body.Close()
-		bodyBytes, _ := io.ReadAll(httpRes.Body)
-		if len(bodyBytes) > 0 {
-			if ok := client.Backoff(attempts); !ok {
-				log.Printf("[ERROR] Authentication failed: Invalid credentials")
-				return fmt.Errorf("authentication failed, invalid credentials")
-			} else {
-				log.Printf("[ERROR] Authentication failed: %s, retries: %v", err, attempts)
-				continue
+// PostBody makes a POST request with a Body and returns a GJSON result.
+// The body is validated before sending the request.
+func (client *Client) PostBody(path string, body Body, mods ...func(*Req)) (Res, error) {
+	if err := body.Validate(); err != nil {
+		return Res{}, err
+	}
+	return client.Post(path, body.Str, mods...)
+}
+
+// PutBody makes a PUT request with a Body and returns a GJSON result.
+// The body is validated before sending the request.
+func (client *Client) PutBody(path string, body Body, mods ...func(*Req)) (Res, error) {
+	if err := body.Validate(); err != nil {
+		return Res{}, err
+	}
+	return client.Put(path, body.Str, mods...)
+}
+
+// createdIDFields are the response body fields containing the ID of a created resource, see CreateAndGetID.
+var createdIDFields = []string{"templateId", "definitionId", "listId", "id"}
+
+// CreateAndGetID makes a POST request and returns the ID of the created resource along with the response.
+// The ID is taken from the last path segment of the Location header if present, otherwise from one of the
+// body fields "templateId", "definitionId", "listId" or "id". An error wrapping ErrNotFound is returned if none is found.
+func (client *Client) CreateAndGetID(path, data string, mods ...func(*Req)) (string, Res, error) {
+	res, err := client.Post(path, data, mods...)
+	if err != nil {
+		return "", res, err
+	}
+	if location := res.Header.Get("Location"); location != "" {
+		if u, err := url.Parse(location); err == nil {
+			segments := strings.Split(strings.TrimSuffix(u.Path, "/"), "/")
+			if id := segments[len(segments)-1]; id != "" {
+				return id, res, nil
 			}
 		}
-		req = client.NewReq("GET", "/dataservice/client/token", nil)
-		httpRes, err = client.HttpClient.Do(req.HttpReq)
+	}
+	for _, field := range createdIDFields {
+		if id := res.Get(field).String(); id != "" {
+			return id, res, nil
+		}
+	}
+	return "", res, fmt.Errorf("ID of resource created at %s: %w", path, ErrNotFound)
+}
+
+// PatchMerge updates an object by retrieving it, deep merging the patch into it and writing it back using PUT.
+// This avoids having to send the complete object when only a few attributes change, see Body.Merge.
+func (client *Client) PatchMerge(path string, patch Body, mods ...func(*Req)) (Res, error) {
+	res, err := client.Get(path, mods...)
+	if err != nil {
+		return res, err
+	}
+	body := Body{Str: res.Raw}.Merge(patch)
+	return client.Put(path, body.Str, mods...)
+}
+
+// MergePatch makes a PATCH request with a JSON merge patch document (RFC 7386) and returns a GJSON result.
+// Only the attributes contained in the document are changed, null values remove attributes. In contrast to
+// PatchMerge, the merge is done by vManage, which is supported by config group feature profiles on some releases.
+// The document is validated before sending the request.
+func (client *Client) MergePatch(path string, mergeDoc []byte, mods ...func(*Req)) (Res, error) {
+	if err := (Body{Str: string(mergeDoc)}).Validate(); err != nil {
+		return Res{}, err
+	}
+	req := client.NewReq("PATCH", "/dataservice"+path, bytes.NewReader(mergeDoc), mods...)
+	req.HttpReq.Header.Set("Content-Type", "application/merge-patch+json")
+	if !req.NoAuth {
+		err := client.Authenticate()
 		if err != nil {
+			return Res{}, err
+		}
+	}
+	return client.Do(req)
+}
+
+// Login authenticates to the SDWAN vManage device.
+func (client *Client) Login() error {
+	data := url.Values{}
+	data.Set("j_username", client.Usr)
+	data.Set("j_password", client.Pwd)
+	failovers := 0
+	for attempts := 0; ; attempts++ {
+		// failovers to another host are not counted as retries
+		retries := attempts - failovers
+		req := client.NewReq("POST", client.AuthLoginPath, strings.NewReader(data.Encode()), NoLogPayload)
+		req.HttpReq.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+		if err := client.signRequest(req.HttpReq); err != nil {
 			return err
 		}
+		started := client.clockNow()
+		httpRes, err := client.HttpClient.Do(req.HttpReq)
+		if err != nil {
+			if client.loginFailover(failovers) {
+				failovers++
+				continue
+			}
+			if ok := client.LoginBackoff(retries); !ok {
+				log.Printf("[ERROR] Authentication failed: %+v", err)
+				return err
+			} else {
+				log.Printf("[ERROR] Authentication failed: %s, retries: %v", err, retries)
+				continue
+			}
+		}
+		defer httpRes.Body.Close()
+		client.recordExchange(req, nil, httpRes, nil, started)
+		if httpRes.StatusCode == 408 || (httpRes.StatusCode >= 500 && httpRes.StatusCode <= 599) {
+			if client.loginFailover(failovers) {
+				failovers++
+				continue
+			}
+			if ok := client.LoginBackoff(retries); !ok {
+				log.Printf("[ERROR] Authentication failed: StatusCode %v", httpRes.StatusCode)
+				return fmt.Errorf("%w, status code: %v", ErrAuthFailed, httpRes.StatusCode)
+			} else {
+				log.Printf("[ERROR] Authentication failed: StatusCode %v, retries: %v", httpRes.StatusCode, retries)
+				continue
+			}
+		}
 		if httpRes.StatusCode != 200 {
-			log.Printf("[ERROR] Token retrieval failed: StatusCode %v", httpRes.StatusCode)
-			return fmt.Errorf("authentication failed, token retrieval, status code: %v", httpRes.StatusCode)
+			log.Printf("[ERROR] Authentication failed: StatusCode %v", httpRes.StatusCode)
+			return fmt.Errorf("%w, status code: %v", ErrAuthFailed, httpRes.StatusCode)
+		}
+		bodyBytes, _ := io.ReadAll(httpRes.Body)
+		if isLoginPage(bodyBytes) {
+			// vManage returns the login page again if the credentials are invalid, retrying will not help
+			log.Printf("[ERROR] Authentication failed: Invalid credentials")
+			return ErrInvalidCredentials
+		}
+		if len(bodyBytes) > 0 {
+			// e.g. an error page of a proxy or a maintenance page
+			if client.loginFailover(failovers) {
+				failovers++
+				continue
+			}
+			if ok := client.LoginBackoff(retries); !ok {
+				log.Printf("[ERROR] Authentication failed: Unexpected response: %s", bodyBytes)
+				return fmt.Errorf("%w, unexpected response", ErrAuthFailed)
+			} else {
+				log.Printf("[ERROR] Authentication failed: Unexpected response, retries: %v", retries)
+				continue
+			}
+		}
+		if err := client.fetchToken(); err != nil {
+			// the session is bound to the host, log in again on the next host
+			if client.loginFailover(failovers) {
+				failovers++
+				continue
+			}
+			return err
+		}
+		client.tokenExpiry = sessionExpiry(httpRes.Cookies(), client.clockNow())
+		client.logins++
+		log.Printf("[DEBUG] Authentication successful, logins: %v", client.logins)
+		return nil
+	}
+}
+
+// sessionExpiry returns the earliest expiry time of the session cookies, zero if none of the cookies expires.
+func sessionExpiry(cookies []*http.Cookie, now time.Time) time.Time {
+	var expiry time.Time
+	for _, cookie := range cookies {
+		var cookieExpiry time.Time
+		if cookie.MaxAge > 0 {
+			cookieExpiry = now.Add(time.Duration(cookie.MaxAge) * time.Second)
+		} else if !cookie.Expires.IsZero() {
+			cookieExpiry = cookie.Expires
+		} else {
+			continue
+		}
+		if expiry.IsZero() || cookieExpiry.Before(expiry) {
+			expiry = cookieExpiry
+		}
+	}
+	return expiry
+}
+
+// TokenExpiry returns the time the current session is expected to expire.
+// The expiry is derived from the session cookies returned by the login, a zero time is returned if unknown.
+func (client *Client) TokenExpiry() time.Time {
+	client.AuthenticationMutex.Lock()
+	defer client.AuthenticationMutex.Unlock()
+	return client.tokenExpiry
+}
+
+// IsAuthenticated checks whether a token of a session which has not expired is available.
+// In contrast to Authenticate, no login is triggered.
+func (client *Client) IsAuthenticated() bool {
+	client.AuthenticationMutex.Lock()
+	defer client.AuthenticationMutex.Unlock()
+	return client.Token != "" && !client.tokenExpired()
+}
+
+// LoginCount returns the number of successful logins, e.g. to detect a client creating excessive sessions.
+func (client *Client) LoginCount() int {
+	client.AuthenticationMutex.Lock()
+	defer client.AuthenticationMutex.Unlock()
+	return client.logins
+}
+
+// ReauthCount returns the number of authentications which replaced a previous session, e.g. after it expired.
+func (client *Client) ReauthCount() int {
+	client.AuthenticationMutex.Lock()
+	defer client.AuthenticationMutex.Unlock()
+	return client.reauths
+}
+
+// TokenAge returns the time since the current token has been retrieved, zero if no token is available.
+func (client *Client) TokenAge() time.Duration {
+	client.AuthenticationMutex.Lock()
+	defer client.AuthenticationMutex.Unlock()
+	if client.Token == "" || client.tokenIssued.IsZero() {
+		return 0
+	}
+	return client.clockNow().Sub(client.tokenIssued)
+}
+
+// tokenExpired checks whether the current session has expired.
+func (client *Client) tokenExpired() bool {
+	return !client.tokenExpiry.IsZero() && client.clockNow().After(client.tokenExpiry)
+}
+
+// fetchToken retrieves the XSRF token of the current session.
+// Transient failures are retried using the login specific retry settings.
+func (client *Client) fetchToken() error {
+	for attempts := 0; ; attempts++ {
+		req := client.NewReq("GET", client.AuthTokenPath, nil, NoLogPayload)
+		if err := client.signRequest(req.HttpReq); err != nil {
+			return err
+		}
+		started := client.clockNow()
+		httpRes, err := client.HttpClient.Do(req.HttpReq)
+		if err != nil {
+			if ok := client.LoginBackoff(attempts); !ok {
+				log.Printf("[ERROR] Token retrieval failed: %+v", err)
+				return err
+			} else {
+				log.Printf("[ERROR] Token retrieval failed: %s, retries: %v", err, attempts)
+				continue
+			}
 		}
 		defer httpRes.Body.Close()
+		client.recordExchange(req, nil, httpRes, nil, started)
+		if httpRes.StatusCode == 408 || (httpRes.StatusCode >= 500 && httpRes.StatusCode <= 599) {
+			if ok := client.LoginBackoff(attempts); !ok {
+				log.Printf("[ERROR] Token retrieval failed: StatusCode %v", httpRes.StatusCode)
+				return fmt.Errorf("%w, status code: %v", ErrTokenRetrieval, httpRes.StatusCode)
+			} else {
+				log.Printf("[ERROR] Token retrieval failed: StatusCode %v, retries: %v", httpRes.StatusCode, attempts)
+				continue
+			}
+		}
+		if httpRes.StatusCode != 200 {
+			log.Printf("[ERROR] Token retrieval failed: StatusCode %v", httpRes.StatusCode)
+			return fmt.Errorf("%w, status code: %v", ErrTokenRetrieval, httpRes.StatusCode)
+		}
 		token, _ := io.ReadAll(httpRes.Body)
 		if string(token) == "" {
 			log.Printf("[ERROR] Token retrieval failed: no token in payload")
-			return fmt.Errorf("authentication failed, no token in payload")
+			return fmt.Errorf("%w, no token in payload", ErrTokenRetrieval)
 		}
 		client.Token = string(token)
-		log.Printf("[DEBUG] Authentication successful")
+		client.tokenIssued = client.clockNow()
+		return nil
+	}
+}
+
+// SetCookie adds a cookie for the vManage URL to the cookie jar of the client.
+// This can be used to reuse a session established outside of the client, e.g. by an SSO flow.
+func (client *Client) SetCookie(cookie *http.Cookie) error {
+	return client.SetCookies([]*http.Cookie{cookie})
+}
+
+// SetCookies adds multiple cookies for the vManage URL to the cookie jar of the client.
+func (client *Client) SetCookies(cookies []*http.Cookie) error {
+	if client.HttpClient.Jar == nil {
+		return fmt.Errorf("the HTTP client has no cookie jar")
+	}
+	u, err := url.Parse(client.currentURL())
+	if err != nil {
+		return err
+	}
+	client.HttpClient.Jar.SetCookies(u, cookies)
+	return nil
+}
+
+// requestCookieJar wraps a cookie jar and hides the cookies overridden by the cookies of a single request.
+type requestCookieJar struct {
+	http.CookieJar
+	cookies []*http.Cookie
+}
+
+func (jar requestCookieJar) Cookies(u *url.URL) []*http.Cookie {
+	var cookies []*http.Cookie
+	for _, cookie := range jar.CookieJar.Cookies(u) {
+		if !hasCookie(jar.cookies, cookie.Name) {
+			cookies = append(cookies, cookie)
+		}
+	}
+	return cookies
+}
+
+// replaceCookie replaces the value of a cookie in the Cookie header of a request.
+func replaceCookie(req *http.Request, cookie *http.Cookie) {
+	cookies := req.Cookies()
+	req.Header.Del("Cookie")
+	for _, c := range cookies {
+		if c.Name != cookie.Name {
+			req.AddCookie(c)
+		}
+	}
+	req.AddCookie(cookie)
+}
+
+// hasCookie checks whether a cookie with the given name is part of a list of cookies.
+func hasCookie(cookies []*http.Cookie, name string) bool {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsJSON checks whether a request accepts a JSON response, which is the default if no Accept header is set.
+func acceptsJSON(req *http.Request) bool {
+	accept := req.Header.Get("Accept")
+	return accept == "" || strings.Contains(accept, "json") || strings.Contains(accept, "*/*")
+}
+
+// acquireRequestSlot blocks until the number of requests in flight is below MaxConcurrentRequests.
+func (client *Client) acquireRequestSlot(ctx context.Context, priority int) error {
+	if client.requestSlots == nil {
+		return nil
+	}
+	return client.requestSlots.acquire(ctx, priority)
+}
+
+// releaseRequestSlot releases a slot acquired with acquireRequestSlot.
+func (client *Client) releaseRequestSlot() {
+	if client.requestSlots != nil {
+		client.requestSlots.release()
+	}
+}
+
+// setBody sets the content length and the body replay function of a request.
+// A known content length avoids chunked transfer encoding, which is rejected by some proxies.
+func setBody(req *http.Request, body []byte) {
+	req.ContentLength = int64(len(body))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+}
+
+// gzipBody compresses a request body.
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// isJSONContentType checks whether a Content-Type header denotes JSON, e.g. "application/json;charset=UTF-8".
+func isJSONContentType(contentType string) bool {
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// bodySnippet returns the beginning of a response body for error messages.
+func bodySnippet(body []byte) string {
+	const maxLength = 200
+	if len(body) > maxLength {
+		return string(body[:maxLength]) + "..."
+	}
+	return string(body)
+}
+
+// responseError creates the error returned for a failed request, a readable message of XML error bodies is included.
+func responseError(httpRes *http.Response, body []byte) error {
+	err := statusError(httpRes.StatusCode)
+	if message, ok := xmlErrorMessage(httpRes.Header.Get("Content-Type"), body); ok {
+		log.Printf("[ERROR] XML error: %s", message)
+		return fmt.Errorf("%w, XML error: %s", err, message)
+	}
+	return err
+}
+
+// xmlErrorMessages are the elements of XML error bodies containing a readable message, e.g. of SOAP faults.
+var xmlErrorMessages = map[string]bool{
+	"faultstring": true,
+	"message":     true,
+	"reason":      true,
+	"description": true,
+	"detail":      true,
+}
+
+// xmlErrorMessage extracts a readable message from an XML error body. If no known message element is found,
+// the beginning of the body is returned. XML is detected by the content type or a leading "<?xml".
+func xmlErrorMessage(contentType string, body []byte) (string, bool) {
+	trimmed := bytes.TrimSpace(body)
+	if !strings.Contains(strings.ToLower(contentType), "xml") && !bytes.HasPrefix(trimmed, []byte("<?xml")) {
+		return "", false
+	}
+	var messages []string
+	decoder := xml.NewDecoder(bytes.NewReader(trimmed))
+	element := ""
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			break
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			element = strings.ToLower(t.Name.Local)
+		case xml.EndElement:
+			element = ""
+		case xml.CharData:
+			if text := strings.TrimSpace(string(t)); text != "" && xmlErrorMessages[element] {
+				messages = append(messages, text)
+			}
+		}
+	}
+	if len(messages) == 0 {
+		return bodySnippet(trimmed), true
+	}
+	return strings.Join(messages, ", "), true
+}
+
+// statusError creates the error returned for a failed request with a given HTTP status code.
+func statusError(statusCode int) error {
+	if statusCode == 404 {
+		return fmt.Errorf("%w: HTTP Request failed: StatusCode %v", ErrNotFound, statusCode)
+	}
+	return fmt.Errorf("HTTP Request failed: StatusCode %v", statusCode)
+}
+
+// signRequest invokes the SignRequest hook if set and restores the request body afterwards.
+func (client *Client) signRequest(req *http.Request) error {
+	if client.SignRequest == nil {
 		return nil
 	}
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	var body []byte
+	if hasBody {
+		body, _ = io.ReadAll(req.Body)
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	if err := client.SignRequest(req); err != nil {
+		return err
+	}
+	if hasBody {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	return nil
+}
+
+// formatPayload formats a payload for logging, JSON payloads are indented if PrettyLog is enabled.
+func (client *Client) formatPayload(payload []byte) string {
+	if client.PrettyLog && gjson.ValidBytes(payload) {
+		return gjson.GetBytes(payload, "@pretty").Raw
+	}
+	return string(payload)
+}
+
+// isLoginPage checks whether a response body is the HTML login page, which submits the credentials to
+// j_security_check. Other HTML pages, e.g. error pages of a proxy, are not login pages.
+func isLoginPage(body []byte) bool {
+	return isHTML(body) && bytes.Contains(body, []byte("j_security_check"))
+}
+
+// isHTML checks whether a response body is an HTML page.
+// The body is searched in place as it is checked for every response.
+func isHTML(body []byte) bool {
+	const tag = "<html"
+	for i := bytes.IndexByte(body, '<'); i >= 0 && i+len(tag) <= len(body); {
+		if bytes.EqualFold(body[i:i+len(tag)], []byte(tag)) {
+			return true
+		}
+		next := bytes.IndexByte(body[i+1:], '<')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return false
+}
+
+// bufferPool holds buffers used to read request and response bodies, see readAll.
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+// maxPooledBufferSize is the capacity above which buffers are not returned to the pool,
+// to avoid retaining the memory of a few very large responses.
+const maxPooledBufferSize = 4 << 20
+
+// readAll reads a body using a pooled buffer and returns a copy of exactly the body size.
+// Compared to io.ReadAll, the buffer does not have to grow for every request, which reduces the
+// allocations of clients sending many requests. The returned slice is owned by the caller.
+func readAll(r io.Reader) ([]byte, error) {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	_, err := buf.ReadFrom(r)
+	data := make([]byte, buf.Len())
+	copy(data, buf.Bytes())
+	if buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+	return data, err
 }
 
-// Login if no token available.
+// authExpired checks whether a response indicates an expired session using the AuthExpiryDetector.
+func (client *Client) authExpired(res Res, statusCode int) bool {
+	if client.AuthExpiryDetector == nil {
+		return DefaultAuthExpiryDetector(res, statusCode)
+	}
+	return client.AuthExpiryDetector(res, statusCode)
+}
+
+// reauthenticate discards an expired token and authenticates again.
+// If another request has already replaced the expired token, the new token is used.
+func (client *Client) reauthenticate(expiredToken string) error {
+	client.AuthenticationMutex.Lock()
+	if client.Token == expiredToken {
+		client.Token = ""
+	}
+	client.invalidateSession(expiredToken)
+	client.AuthenticationMutex.Unlock()
+	return client.Authenticate()
+}
+
+// Login if no token available or the session has expired.
+// If ExternalAuth is enabled, only the token is retrieved using the externally provided session cookie.
 func (client *Client) Authenticate() error {
+	if client.isClosed() {
+		return ErrClientClosed
+	}
 	var err error
 	client.AuthenticationMutex.Lock()
-	if client.Token == "" {
+	if client.tokenExpired() {
+		log.Printf("[DEBUG] Session expired at %v", client.tokenExpiry)
+		client.Token = ""
+		client.tokenExpiry = time.Time{}
+	}
+	if client.Token == "" && !client.tokenIssued.IsZero() {
+		// a previous session has expired or has been invalidated
+		client.reauths++
+		client.tokenIssued = time.Time{}
+	}
+	if client.Token == "" && client.adoptSession() {
+		log.Printf("[DEBUG] Using session of client pool")
+	} else if client.Token == "" && client.ExternalAuth {
+		for failovers := 0; ; failovers++ {
+			err = client.fetchToken()
+			if err == nil || !client.loginFailover(failovers) {
+				break
+			}
+		}
+		if err == nil {
+			client.publishSession()
+		}
+	} else if client.Token == "" {
 		err = client.Login()
+		if err == nil {
+			client.publishSession()
+		}
 	}
 	client.AuthenticationMutex.Unlock()
 	return err
@@ -343,24 +1532,308 @@ func (client *Client) Authenticate() error {
 
 // Backoff waits following an exponential backoff algorithm
 func (client *Client) Backoff(attempts int) bool {
-	log.Printf("[DEBUG] Begining backoff method: attempts %v on %v", attempts, client.MaxRetries)
-	if attempts >= client.MaxRetries {
+	return backoff(attempts, client.MaxRetries, client.BackoffMinDelay, client.BackoffMaxDelay, client.BackoffDelayFactor, client.clockSleep)
+}
+
+// MigrateSession moves the client to another vManage host, e.g. another cluster member during maintenance.
+// The cookie jar only sends the session cookie (JSESSIONID) to the host which issued it, therefore the
+// session of the current host can not be used on the new one. MigrateSession authenticates against the new
+// host and repoints the client, subsequent requests use the new session. If the authentication fails, the
+// client keeps using the current host and session.
+func (client *Client) MigrateSession(newURL string) error {
+	u, err := url.Parse(newURL)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid URL: %s", newURL)
+	}
+	client.AuthenticationMutex.Lock()
+	oldURL, oldToken, oldExpiry, oldIssued := client.Url, client.Token, client.tokenExpiry, client.tokenIssued
+	log.Printf("[DEBUG] Migrating session from %s to %s", oldURL, newURL)
+	client.setURL(newURL)
+	client.Token = ""
+	client.tokenExpiry = time.Time{}
+	client.AuthenticationMutex.Unlock()
+
+	if err := client.Authenticate(); err != nil {
+		log.Printf("[ERROR] Session migration to %s failed: %s", newURL, err)
+		client.AuthenticationMutex.Lock()
+		client.setURL(oldURL)
+		client.Token = oldToken
+		client.tokenExpiry = oldExpiry
+		client.tokenIssued = oldIssued
+		client.AuthenticationMutex.Unlock()
+		return fmt.Errorf("session migration to %s failed: %w", newURL, err)
+	}
+	return nil
+}
+
+// failover switches the client to the next host after a request failed and updates the request accordingly.
+// It returns false if no other host is configured or every host has already been tried for this request.
+func (client *Client) failover(req *Req, failovers int) bool {
+	if len(client.Hosts) < 2 || failovers >= len(client.Hosts)-1 {
+		return false
+	}
+	failedHost := req.HttpReq.URL.Scheme + "://" + req.HttpReq.URL.Host
+	client.AuthenticationMutex.Lock()
+	// another request might already have failed over
+	if strings.TrimSuffix(client.Url, "/") == failedHost {
+		client.switchHost()
+	}
+	client.AuthenticationMutex.Unlock()
+
+	if !req.NoAuth {
+		if err := client.Authenticate(); err != nil {
+			req.logf("[ERROR] Authentication against %s failed: %s", client.currentURL(), err)
+		}
+		req.HttpReq.Header.Set("X-XSRF-TOKEN", client.Token)
+	}
+	// the authentication might have failed over to yet another host
+	u, err := url.Parse(client.currentURL())
+	if err != nil {
+		return false
+	}
+	req.HttpReq.URL.Scheme = u.Scheme
+	req.HttpReq.URL.Host = u.Host
+	req.HttpReq.Host = u.Host
+	return true
+}
+
+// loginFailover switches the client to the next host after a login or token retrieval failed.
+// The caller must hold the AuthenticationMutex. It returns false if no other host is configured
+// or every host has already been tried for this login.
+func (client *Client) loginFailover(failovers int) bool {
+	if len(client.Hosts) < 2 || failovers >= len(client.Hosts)-1 {
+		return false
+	}
+	client.switchHost()
+	return true
+}
+
+// switchHost makes the next host the active one and discards the session, the caller must hold the AuthenticationMutex.
+func (client *Client) switchHost() {
+	next := client.Hosts[0]
+	for i, host := range client.Hosts {
+		if host == client.Url {
+			next = client.Hosts[(i+1)%len(client.Hosts)]
+		}
+	}
+	log.Printf("[WARNING] Failing over from %s to %s", client.Url, next)
+	client.setURL(next)
+	client.Token = ""
+	client.tokenExpiry = time.Time{}
+}
+
+// currentURL returns the URL of the active host, see Hosts.
+func (client *Client) currentURL() string {
+	if client.urlMutex == nil {
+		return client.Url
+	}
+	client.urlMutex.RLock()
+	defer client.urlMutex.RUnlock()
+	return client.Url
+}
+
+// setURL changes the URL of the active host, the caller must hold the AuthenticationMutex.
+func (client *Client) setURL(u string) {
+	if client.urlMutex == nil {
+		client.Url = u
+		return
+	}
+	client.urlMutex.Lock()
+	client.Url = u
+	client.urlMutex.Unlock()
+}
+
+// lockErrorMarkers identify error messages caused by a configuration lock held by another operation.
+var lockErrorMarkers = []string{"in progress", "locked", "lock acquisition"}
+
+// isLockError checks whether a response is an error caused by a configuration lock held by another operation.
+func isLockError(res Res) bool {
+	if !res.Get("error").Exists() {
+		return false
+	}
+	message := strings.ToLower(res.Get("error.code").String() + " " + res.Get("error.message").String() + " " + res.Get("error.details").String())
+	for _, marker := range lockErrorMarkers {
+		if strings.Contains(message, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// waitForLock waits following an exponential backoff algorithm for a configuration lock to be released.
+// It returns false once the lock wait timeout has expired.
+func (client *Client) waitForLock(req Req, lockWaitStart time.Time, lockRetries int) bool {
+	timeout := time.Duration(client.LockWaitTimeout) * time.Second
+	if client.clockNow().Sub(lockWaitStart) >= timeout {
+		req.logf("[ERROR] Configuration lock not released after %v", timeout)
+		return false
+	}
+	delay := client.requestBackoffDelay(lockRetries)
+	req.logf("[WARNING] Configuration locked by another operation, waiting %v, retries: %v", delay.Round(time.Second), lockRetries)
+	client.clockSleepContext(req.HttpReq.Context(), delay)
+	return true
+}
+
+// retry checks whether another attempt of a failed request is allowed and waits for the given delay if so.
+// Each retry is logged as a structured record with the attempt number, the reason, the status code and the delay.
+// The wait ends early if the request context is canceled, the next attempt then returns the context error.
+func (client *Client) retry(req Req, attempts int, reason string, statusCode int, delay time.Duration) bool {
+	maxRetries := nonNegative(req.MaxRetries)
+	if attempts >= maxRetries {
+		req.logf("[ERROR] HTTP Request retries exhausted: method=%s url=%s attempt=%d max_retries=%d reason=%s status_code=%d",
+			req.HttpReq.Method, req.HttpReq.URL, attempts+1, maxRetries, reason, statusCode)
+		return false
+	}
+	req.logf("[WARNING] HTTP Request retry: method=%s url=%s attempt=%d max_retries=%d reason=%s status_code=%d next_delay=%v",
+		req.HttpReq.Method, req.HttpReq.URL, attempts+1, maxRetries, reason, statusCode, delay)
+	client.clockSleepContext(req.HttpReq.Context(), delay)
+	return true
+}
+
+// contextError returns an error wrapping the context error if the context of a request is canceled or expired.
+func contextError(req Req) error {
+	if err := req.HttpReq.Context().Err(); err != nil {
+		req.logf("[ERROR] HTTP Request canceled: %s", err)
+		return fmt.Errorf("HTTP Request canceled: %w", err)
+	}
+	return nil
+}
+
+// requestBackoffDelay calculates the backoff delay of a request for a given attempt.
+func (client *Client) requestBackoffDelay(attempts int) time.Duration {
+	return backoffDelay(attempts, client.BackoffMinDelay, client.BackoffMaxDelay, client.BackoffDelayFactor)
+}
+
+// LoginBackoff waits following an exponential backoff algorithm using the login specific retry settings
+func (client *Client) LoginBackoff(attempts int) bool {
+	return backoff(attempts, client.LoginMaxRetries, client.LoginBackoffMinDelay, client.LoginBackoffMaxDelay, client.LoginBackoffDelayFactor, client.clockSleep)
+}
+
+func backoff(attempts, maxRetries, backoffMinDelay, backoffMaxDelay int, backoffDelayFactor float64, sleep func(time.Duration)) bool {
+	log.Printf("[DEBUG] Begining backoff method: attempts %v on %v", attempts, maxRetries)
+	if attempts >= maxRetries {
 		log.Printf("[DEBUG] Exit from backoff method with return value false")
 		return false
 	}
 
-	minDelay := time.Duration(client.BackoffMinDelay) * time.Second
-	maxDelay := time.Duration(client.BackoffMaxDelay) * time.Second
+	backoffDuration := backoffDelay(attempts, backoffMinDelay, backoffMaxDelay, backoffDelayFactor)
+	log.Printf("[TRACE] Starting sleeping for %v", backoffDuration.Round(time.Second))
+	sleep(backoffDuration)
+	log.Printf("[DEBUG] Exit from backoff method with return value true")
+	return true
+}
+
+// newCorrelationID generates a correlation ID using the CorrelationIDGenerator or a random UUID.
+func (client *Client) newCorrelationID() string {
+	if client.CorrelationIDGenerator != nil {
+		return client.CorrelationIDGenerator()
+	}
+	var uuid [16]byte
+	if _, err := cryptorand.Read(uuid[:]); err != nil {
+		return ""
+	}
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+	var id [36]byte
+	hex.Encode(id[0:8], uuid[0:4])
+	id[8] = '-'
+	hex.Encode(id[9:13], uuid[4:6])
+	id[13] = '-'
+	hex.Encode(id[14:18], uuid[6:8])
+	id[18] = '-'
+	hex.Encode(id[19:23], uuid[8:10])
+	id[23] = '-'
+	hex.Encode(id[24:], uuid[10:])
+	return string(id[:])
+}
+
+// nonNegative treats negative numbers of retries as 0.
+func nonNegative(x int) int {
+	if x < 0 {
+		return 0
+	}
+	return x
+}
+
+// clockNow returns the current time, see Client.now.
+func (client *Client) clockNow() time.Time {
+	if client.now != nil {
+		return client.now()
+	}
+	return time.Now()
+}
+
+// clockSleep waits for the given duration, see Client.sleep.
+func (client *Client) clockSleep(d time.Duration) {
+	if client.sleep != nil {
+		client.sleep(d)
+		return
+	}
+	time.Sleep(d)
+}
+
+// clockSleepContext waits for the given duration or until the context is done, see Client.sleep.
+func (client *Client) clockSleepContext(ctx context.Context, d time.Duration) {
+	if client.sleep != nil {
+		if ctx.Err() == nil {
+			client.sleep(d)
+		}
+		return
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// parseRetryAfter parses a Retry-After header, which is either a number of seconds or an HTTP date relative to now.
+// It returns false if the header is missing or invalid.
+func parseRetryAfter(retryAfter string, now time.Time) (time.Duration, bool) {
+	if retryAfter == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseFloat(retryAfter, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds * float64(time.Second)), true
+	}
+	if date, err := http.ParseTime(retryAfter); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// jitter randomly increases or decreases a delay by up to the given fraction.
+func jitter(delay time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return delay
+	}
+	if fraction > 1 {
+		fraction = 1
+	}
+	return time.Duration(float64(delay) * (1 + fraction*(2*rand.Float64()-1)))
+}
+
+// backoffDelay calculates the exponential backoff delay including jitter for a given attempt.
+func backoffDelay(attempts, backoffMinDelay, backoffMaxDelay int, backoffDelayFactor float64) time.Duration {
+	minDelay := time.Duration(backoffMinDelay) * time.Second
+	maxDelay := time.Duration(backoffMaxDelay) * time.Second
 
 	min := float64(minDelay)
-	backoff := min * math.Pow(client.BackoffDelayFactor, float64(attempts))
+	backoff := min * math.Pow(backoffDelayFactor, float64(attempts))
 	if backoff > float64(maxDelay) {
 		backoff = float64(maxDelay)
 	}
 	backoff = (rand.Float64()/2+0.5)*(backoff-min) + min
-	backoffDuration := time.Duration(backoff)
-	log.Printf("[TRACE] Starting sleeping for %v", backoffDuration.Round(time.Second))
-	time.Sleep(backoffDuration)
-	log.Printf("[DEBUG] Exit from backoff method with return value true")
-	return true
+	return time.Duration(backoff)
 }
@@ -1,9 +1,21 @@
 package sdwan
 
 import (
+	"bytes"
+	"compress/gzip"
+	"context"
 	"errors"
 	"io"
+	"log"
 	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -16,7 +28,7 @@ const (
 )
 
 func testClient() Client {
-	client, _ := NewClient(testURL, "usr", "pwd", true, MaxRetries(0))
+	client, _ := NewClient(testURL, "usr", "pwd", true, MaxRetries(0), LoginMaxRetries(0))
 	gock.InterceptClient(client.HttpClient)
 	return client
 }
@@ -27,6 +39,34 @@ func authenticatedTestClient() Client {
 	return client
 }
 
+// fakeClock is a clock which only advances when sleeping.
+type fakeClock struct {
+	mutex sync.Mutex
+	now   time.Time
+	slept []time.Duration
+}
+
+func (clock *fakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.now
+}
+
+func (clock *fakeClock) Sleep(d time.Duration) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	clock.now = clock.now.Add(d)
+	clock.slept = append(clock.slept, d)
+}
+
+// withClock replaces the clock of a client, used by tests only.
+func withClock(clock *fakeClock) func(*Client) {
+	return func(client *Client) {
+		client.now = clock.Now
+		client.sleep = clock.Sleep
+	}
+}
+
 // ErrReader implements the io.Reader interface and fails on Read.
 type ErrReader struct{}
 
@@ -41,6 +81,25 @@ func TestNewClient(t *testing.T) {
 	assert.Equal(t, client.HttpClient.Timeout, 120*time.Second)
 }
 
+// TestClientCookieJar tests the CookieJar modifier.
+func TestClientCookieJar(t *testing.T) {
+	defer gock.Off()
+	jar, _ := cookiejar.New(nil)
+	client, err := NewClient(testURL, "usr", "pwd", true, MaxRetries(0), CookieJar(jar))
+	assert.NoError(t, err)
+	gock.InterceptClient(client.HttpClient)
+
+	// Session cookie shared with another client
+	gock.New(testURL).Post("/j_security_check").Reply(200).SetHeader("Set-Cookie", "JSESSIONID=XYZ")
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("ABC")
+	assert.NoError(t, client.Login())
+	u, _ := url.Parse(testURL)
+	assert.Equal(t, "XYZ", jar.Cookies(u)[0].Value)
+
+	_, err = NewClient(testURL, "usr", "pwd", true, CookieJar(nil))
+	assert.Error(t, err)
+}
+
 // TestClientLogin tests the Client::Login method.
 func TestClientLogin(t *testing.T) {
 	defer gock.Off()
@@ -54,11 +113,110 @@ func TestClientLogin(t *testing.T) {
 	// Unsuccessful token retrieval
 	gock.New(testURL).Post("/j_security_check").Reply(200)
 	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("")
-	assert.Error(t, client.Login())
+	assert.ErrorIs(t, client.Login(), ErrTokenRetrieval)
 
 	// Invalid HTTP status code
 	gock.New(testURL).Post("/j_security_check").Reply(405)
+	assert.ErrorIs(t, client.Login(), ErrAuthFailed)
+
+	// Invalid credentials are not retried
+	client.LoginMaxRetries = 3
+	gock.New(testURL).Post("/j_security_check").Reply(200).BodyString(`<html><body><form action="j_security_check"></form></body></html>`)
+	err := client.Login()
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.ErrorIs(t, err, ErrAuthFailed)
+	assert.True(t, gock.IsDone())
+
+	// Other HTML pages are retried
+	client.LoginMaxRetries = 1
+	client.LoginBackoffMinDelay = 0
+	gock.New(testURL).Post("/j_security_check").Reply(200).BodyString("<html><body>502 Bad Gateway</body></html>")
+	gock.New(testURL).Post("/j_security_check").Reply(200).BodyString("<html><body>Maintenance</body></html>")
+	err = client.Login()
+	assert.ErrorIs(t, err, ErrAuthFailed)
+	assert.NotErrorIs(t, err, ErrInvalidCredentials)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientLoginCustomPaths tests the AuthLoginPath and AuthTokenPath modifiers.
+func TestClientLoginCustomPaths(t *testing.T) {
+	defer gock.Off()
+	client := testClient()
+	AuthLoginPath("/gw/j_security_check")(&client)
+	AuthTokenPath("/gw/dataservice/client/token")(&client)
+
+	gock.New(testURL).Post("/gw/j_security_check").Reply(200)
+	gock.New(testURL).Get("/gw/dataservice/client/token").Reply(200).BodyString("ABC")
+	assert.NoError(t, client.Login())
+}
+
+// TestClientTokenExpiry tests the Client::TokenExpiry method and the re-authentication of expired sessions.
+func TestClientTokenExpiry(t *testing.T) {
+	defer gock.Off()
+	client := testClient()
+
+	// Expiry derived from the session cookie
+	gock.New(testURL).Post("/j_security_check").Reply(200).SetHeader("Set-Cookie", "JSESSIONID=XYZ; Max-Age=1800")
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("ABC")
+	assert.False(t, client.IsAuthenticated())
+	assert.NoError(t, client.Authenticate())
+	assert.WithinDuration(t, time.Now().Add(1800*time.Second), client.TokenExpiry(), 5*time.Second)
+	assert.True(t, client.IsAuthenticated())
+
+	// Expired session triggers a new login
+	client.tokenExpiry = time.Now().Add(-time.Second)
+	assert.False(t, client.IsAuthenticated())
+	gock.New(testURL).Post("/j_security_check").Reply(200)
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("DEF")
+	assert.NoError(t, client.Authenticate())
+	assert.Equal(t, "DEF", client.Token)
+	assert.True(t, client.TokenExpiry().IsZero())
+	assert.Equal(t, 2, client.LoginCount())
+	assert.Equal(t, 1, client.ReauthCount())
+	assert.Greater(t, client.TokenAge(), time.Duration(0))
+}
+
+// TestClientLoginRetry tests the login specific retry settings of the Client::Login method.
+func TestClientLoginRetry(t *testing.T) {
+	defer gock.Off()
+	client := testClient()
+	LoginMaxRetries(1)(&client)
+	LoginBackoffMinDelay(0)(&client)
+
+	// Transient error followed by successful login
+	gock.New(testURL).Post("/j_security_check").Reply(503)
+	gock.New(testURL).Post("/j_security_check").Reply(200)
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("ABC")
+	assert.NoError(t, client.Login())
+
+	// Retries exhausted
+	gock.New(testURL).Post("/j_security_check").Times(2).Reply(503)
 	assert.Error(t, client.Login())
+
+	// Transient token retrieval error
+	gock.New(testURL).Post("/j_security_check").Reply(200)
+	gock.New(testURL).Get("/dataservice/client/token").Reply(503)
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("DEF")
+	assert.NoError(t, client.Login())
+	assert.Equal(t, "DEF", client.Token)
+}
+
+// TestClientExternalAuth tests the Client::SetCookie method and the ExternalAuth modifier.
+func TestClientExternalAuth(t *testing.T) {
+	defer gock.Off()
+	client := testClient()
+	ExternalAuth(true)(&client)
+
+	assert.NoError(t, client.SetCookie(&http.Cookie{Name: "JSESSIONID", Value: "XYZ"}))
+	gock.New(testURL).
+		Get("/dataservice/client/token").
+		MatchHeader("Cookie", "JSESSIONID=XYZ").
+		Reply(200).
+		BodyString("ABC")
+	gock.New(testURL).Get("/dataservice/url").MatchHeader("X-XSRF-TOKEN", "ABC").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
 }
 
 // TestClientGet tests the Client::Get method.
@@ -75,12 +233,34 @@ func TestClientGet(t *testing.T) {
 	// HTTP error
 	gock.New(testURL).Get("/url").ReplyError(errors.New("fail"))
 	_, err = client.Get("/url")
-	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrMaxRetriesExceeded)
 
 	// Invalid HTTP status code
 	gock.New(testURL).Get("/url").Reply(405)
 	_, err = client.Get("/url")
 	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrMaxRetriesExceeded)
+
+	// Server error
+	gock.New(testURL).Get("/url").Reply(503)
+	_, err = client.Get("/url")
+	assert.ErrorIs(t, err, ErrMaxRetriesExceeded)
+
+	// Rate limited
+	gock.New(testURL).Get("/url").Reply(429)
+	_, err = client.Get("/url")
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	// JSON error
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`{"error":{"code":"ADVISORY"},"data":[]}`)
+	_, err = client.Get("/url")
+	assert.Error(t, err)
+
+	// JSON error ignored
+	gock.New(testURL).Get("/url").Reply(200).BodyString(`{"error":{"code":"ADVISORY"},"data":[]}`)
+	res, err := client.Get("/url", IgnoreJSONError())
+	assert.NoError(t, err)
+	assert.Equal(t, "ADVISORY", res.Get("error.code").String())
 
 	// Error decoding response body
 	gock.New(testURL).
@@ -94,6 +274,30 @@ func TestClientGet(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// TestClientGetContextCanceled tests that a request is not retried once its context is done.
+func TestClientGetContextCanceled(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+	client, _ := NewClient(server.URL, "usr", "pwd", true, BackoffMinDelay(10))
+	client.Token = "ABC"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	_, err := client.Get("/url", Context(ctx))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, ErrMaxRetriesExceeded)
+	assert.Less(t, time.Since(started), time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
+
 // TestClientDelete tests the Client::Delete method.
 func TestClientDelete(t *testing.T) {
 	defer gock.Off()
@@ -114,6 +318,32 @@ func TestClientDelete(t *testing.T) {
 	assert.Error(t, err)
 }
 
+// TestClientDeleteMany tests the Client::DeleteMany method.
+func TestClientDeleteMany(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Continue on error, missing objects are considered deleted
+	gock.New(testURL).Delete("/dataservice/a").Reply(200)
+	gock.New(testURL).Delete("/dataservice/b").Reply(404)
+	gock.New(testURL).Delete("/dataservice/c").Reply(400)
+	gock.New(testURL).Delete("/dataservice/d").Reply(200)
+	results := client.DeleteMany([]string{"/a", "/b", "/c", "/d"}, true)
+	assert.Len(t, results, 4)
+	assert.True(t, results[0].Success)
+	assert.True(t, results[1].Success)
+	assert.False(t, results[2].Success)
+	assert.Error(t, results[2].Err)
+	assert.True(t, results[3].Success)
+
+	// Stop at first error
+	gock.New(testURL).Delete("/dataservice/a").Reply(400)
+	results = client.DeleteMany([]string{"/a", "/b"}, false)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "/a", results[0].Path)
+	assert.False(t, results[0].Success)
+}
+
 // TestClientDeleteBody tests the Client::Delete method.
 func TestClientDeleteBody(t *testing.T) {
 	defer gock.Off()
@@ -201,3 +431,893 @@ func TestClientPut(t *testing.T) {
 	_, err = client.Put("/url", "{}")
 	assert.Error(t, err)
 }
+
+// TestClientPostBody tests the Client::PostBody method.
+func TestClientPostBody(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Success
+	gock.New(testURL).Post("/url").Reply(200)
+	_, err := client.PostBody("/url", Body{}.Set("name", "a"))
+	assert.NoError(t, err)
+
+	// Invalid body is not sent
+	_, err = client.PostBody("/url", Body{Str: `{"name":`})
+	assert.Error(t, err)
+}
+
+// TestClientRetries tests the Retries request modifier.
+func TestClientRetries(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.BackoffMinDelay = 0
+
+	// No retries for a single request
+	client.MaxRetries = 3
+	gock.New(testURL).Get("/url").Reply(503)
+	gock.New(testURL).Get("/url").Reply(200)
+	_, err := client.Get("/url", Retries(0))
+	assert.ErrorIs(t, err, ErrMaxRetriesExceeded)
+	assert.False(t, gock.IsDone())
+	gock.Flush()
+
+	// Retry a single request
+	client.MaxRetries = 0
+	gock.New(testURL).Get("/url").Reply(503)
+	gock.New(testURL).Get("/url").Reply(200)
+	_, err = client.Get("/url", Retries(1))
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientNode tests the Node request modifier.
+func TestClientNode(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.SetCookie(&http.Cookie{Name: "JSESSIONID", Value: "XYZ"})
+
+	gock.New("https://10.0.0.2").
+		Get("/dataservice/url").
+		MatchHeader("Cookie", "JSESSIONID=XYZ").
+		MatchHeader("X-XSRF-TOKEN", "ABC").
+		Reply(200)
+	_, err := client.Get("/url", Node("10.0.0.2"))
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientSignRequest tests the SignRequest hook.
+func TestClientSignRequest(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	SignRequest(func(req *http.Request) error {
+		body, _ := io.ReadAll(req.Body)
+		req.Header.Set("X-Signature", string(body))
+		return nil
+	})(&client)
+
+	// Signature covers the body which is still sent
+	gock.New(testURL).
+		Post("/dataservice/url").
+		MatchHeader("X-Signature", `{"a":1}`).
+		BodyString(`{"a":1}`).
+		Reply(200)
+	_, err := client.Post("/url", `{"a":1}`)
+	assert.NoError(t, err)
+
+	// Signing error
+	SignRequest(func(req *http.Request) error {
+		return errors.New("fail")
+	})(&client)
+	_, err = client.Post("/url", `{"a":1}`)
+	assert.Error(t, err)
+}
+
+// TestClientFormatPayload tests the PrettyLog modifier.
+func TestClientFormatPayload(t *testing.T) {
+	client := testClient()
+	assert.Equal(t, `{"a":1}`, client.formatPayload([]byte(`{"a":1}`)))
+
+	PrettyLog(true)(&client)
+	assert.Equal(t, "{\n  \"a\": 1\n}\n", client.formatPayload([]byte(`{"a":1}`)))
+	assert.Equal(t, "a,b", client.formatPayload([]byte("a,b")))
+}
+
+// TestClientNoAuth tests the NoAuth request modifier.
+func TestClientNoAuth(t *testing.T) {
+	defer gock.Off()
+	client := testClient()
+
+	gock.New(testURL).
+		Get("/dataservice/client/server").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			return req.Header.Get("X-XSRF-TOKEN") == "", nil
+		}).
+		Reply(200)
+	_, err := client.Get("/client/server", NoAuth())
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientContentLength tests that request bodies are sent with a known content length.
+func TestClientContentLength(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	var contentLength int64
+	matchContentLength := func(req *http.Request, _ *gock.Request) (bool, error) {
+		contentLength = req.ContentLength
+		return true, nil
+	}
+
+	// Body of known length
+	gock.New(testURL).Put("/dataservice/url").AddMatcher(matchContentLength).Reply(200)
+	_, err := client.Put("/url", `{"a":1}`)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), contentLength)
+
+	// Body of unknown length
+	gock.New(testURL).Post("/url").AddMatcher(matchContentLength).Reply(200)
+	req := client.NewReq("POST", "/url", io.MultiReader(strings.NewReader(`{"a":`), strings.NewReader(`1}`)))
+	_, err = client.Do(req)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), contentLength)
+}
+
+// TestClientPatchMerge tests the Client::PatchMerge method.
+func TestClientPatchMerge(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/template/feature/object/1").Reply(200).BodyString(`{"templateName":"a","templateDescription":"old"}`)
+	gock.New(testURL).
+		Put("/dataservice/template/feature/object/1").
+		BodyString(`{"templateName":"a","templateDescription":"new"}`).
+		Reply(200)
+	_, err := client.PatchMerge("/template/feature/object/1", Body{}.Set("templateDescription", "new"))
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientMergePatch tests the Client::MergePatch method.
+func TestClientMergePatch(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).
+		Patch("/dataservice/v1/feature-profile/sdwan/system/P1").
+		MatchHeader("Content-Type", "application/merge-patch+json").
+		BodyString(`{"description":null}`).
+		Reply(200).
+		BodyString(`{"id":"P1"}`)
+	res, err := client.MergePatch("/v1/feature-profile/sdwan/system/P1", []byte(`{"description":null}`))
+	assert.NoError(t, err)
+	assert.Equal(t, "P1", res.Get("id").String())
+
+	_, err = client.MergePatch("/v1/feature-profile/sdwan/system/P1", []byte(`{"description":`))
+	assert.Error(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientCreateAndGetID tests the Client::CreateAndGetID method.
+func TestClientCreateAndGetID(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Post("/dataservice/template/feature").Reply(201).SetHeader("Location", "/dataservice/template/feature/F1")
+	id, _, err := client.CreateAndGetID("/template/feature", `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "F1", id)
+
+	gock.New(testURL).Post("/dataservice/template/policy/definition/data").Reply(200).BodyString(`{"definitionId":"D1"}`)
+	id, _, err = client.CreateAndGetID("/template/policy/definition/data", `{}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "D1", id)
+
+	gock.New(testURL).Post("/dataservice/template/feature").Reply(200).BodyString(`{}`)
+	_, _, err = client.CreateAndGetID("/template/feature", `{}`)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientRetryLog tests the structured logging of retries.
+func TestClientRetryLog(t *testing.T) {
+	defer gock.Off()
+	defer log.SetOutput(os.Stderr)
+	client := authenticatedTestClient()
+	client.BackoffMinDelay = 0
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	gock.New(testURL).Get("/url").Reply(503)
+	gock.New(testURL).Get("/url").Reply(200)
+	_, err := client.Get("/url", Retries(1))
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "attempt=1 max_retries=1 reason=server_error status_code=503 next_delay=0s")
+}
+
+// TestClientMigrateSession tests the Client::MigrateSession method.
+func TestClientMigrateSession(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Failed authentication, the client keeps using the current host
+	gock.New("https://10.0.0.2").Post("/j_security_check").Reply(500)
+	err := client.MigrateSession("https://10.0.0.2")
+	assert.ErrorIs(t, err, ErrAuthFailed)
+	assert.Equal(t, testURL, client.Url)
+	assert.Equal(t, "ABC", client.Token)
+
+	gock.New("https://10.0.0.2").Post("/j_security_check").Reply(200)
+	gock.New("https://10.0.0.2").Get("/dataservice/client/token").Reply(200).BodyString("DEF")
+	gock.New("https://10.0.0.2").Get("/dataservice/url").MatchHeader("X-XSRF-TOKEN", "DEF").Reply(200)
+	assert.NoError(t, client.MigrateSession("https://10.0.0.2"))
+	assert.Equal(t, "https://10.0.0.2", client.Url)
+	_, err = client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	assert.Error(t, client.MigrateSession("10.0.0.3"))
+}
+
+// TestClientHosts tests the failover to another host.
+func TestClientHosts(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	Hosts([]string{"https://10.0.0.2"})(&client)
+	assert.Equal(t, []string{testURL, "https://10.0.0.2"}, client.Hosts)
+
+	// Failover after a connection error including authentication
+	gock.New(testURL).Get("/dataservice/url").ReplyError(errors.New("fail"))
+	gock.New("https://10.0.0.2").Post("/j_security_check").Reply(200)
+	gock.New("https://10.0.0.2").Get("/dataservice/client/token").Reply(200).BodyString("DEF")
+	gock.New("https://10.0.0.2").Get("/dataservice/url").MatchHeader("X-XSRF-TOKEN", "DEF").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://10.0.0.2", client.Url)
+
+	// Subsequent requests use the active host
+	gock.New("https://10.0.0.2").Get("/dataservice/url").Reply(200)
+	_, err = client.Get("/url")
+	assert.NoError(t, err)
+
+	// Failover after a server error, all hosts fail
+	gock.New("https://10.0.0.2").Get("/dataservice/url").Reply(503)
+	gock.New(testURL).Post("/j_security_check").Reply(200)
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("ABC")
+	gock.New(testURL).Get("/dataservice/url").Reply(503)
+	_, err = client.Get("/url")
+	assert.ErrorIs(t, err, ErrMaxRetriesExceeded)
+	assert.Equal(t, testURL, client.Url)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientHostsLoginFailover tests the failover to another host if the first host is down before any login.
+func TestClientHostsLoginFailover(t *testing.T) {
+	defer gock.Off()
+	client := testClient()
+	Hosts([]string{"https://10.0.0.2"})(&client)
+
+	gock.New(testURL).Post("/j_security_check").Reply(500)
+	gock.New("https://10.0.0.2").Post("/j_security_check").Reply(200)
+	gock.New("https://10.0.0.2").Get("/dataservice/client/token").Reply(200).BodyString("DEF")
+	gock.New("https://10.0.0.2").Get("/dataservice/url").MatchHeader("X-XSRF-TOKEN", "DEF").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://10.0.0.2", client.Url)
+
+	// Token retrieval failure logs in again on the next host
+	client.Token = ""
+	gock.New("https://10.0.0.2").Post("/j_security_check").Reply(200)
+	gock.New("https://10.0.0.2").Get("/dataservice/client/token").ReplyError(errors.New("fail"))
+	gock.New(testURL).Post("/j_security_check").Reply(200)
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("GHI")
+	assert.NoError(t, client.Authenticate())
+	assert.Equal(t, testURL, client.Url)
+	assert.Equal(t, "GHI", client.Token)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientHostsConcurrent tests concurrent requests failing over to another host.
+func TestClientHostsConcurrent(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	Hosts([]string{"https://10.0.0.2"})(&client)
+
+	gock.New(testURL).Get("/dataservice/url").Persist().ReplyError(errors.New("fail"))
+	gock.New("https://10.0.0.2").Post("/j_security_check").Persist().Reply(200)
+	gock.New("https://10.0.0.2").Get("/dataservice/client/token").Persist().Reply(200).BodyString("DEF")
+	gock.New("https://10.0.0.2").Get("/dataservice/url").Persist().Reply(200)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			client.Get("/url")
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, "https://10.0.0.2", client.currentURL())
+}
+
+// TestClientWithCookie tests the WithCookie request modifier.
+func TestClientWithCookie(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.SetCookie(&http.Cookie{Name: "JSESSIONID", Value: "JAR"})
+	client.SetCookie(&http.Cookie{Name: "OTHER", Value: "1"})
+
+	gock.New(testURL).
+		Get("/dataservice/url").
+		AddMatcher(func(req *http.Request, _ *gock.Request) (bool, error) {
+			return req.Header.Get("Cookie") == "JSESSIONID=REQ; OTHER=1", nil
+		}).
+		Reply(200)
+	_, err := client.Get("/url", WithCookie(&http.Cookie{Name: "JSESSIONID", Value: "REQ"}))
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientWaitForLock tests the WaitForLock request modifier.
+func TestClientWaitForLock(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.BackoffMinDelay = 0
+	lockError := `{"error":{"code":"DEVICE_LOCK","message":"Another template operation is in progress"}}`
+
+	// Retried until the lock is released
+	gock.New(testURL).Post("/dataservice/url").Reply(400).BodyString(lockError)
+	gock.New(testURL).Post("/dataservice/url").Reply(200).BodyString(lockError)
+	gock.New(testURL).Post("/dataservice/url").Reply(200)
+	_, err := client.Post("/url", "{}", WaitForLock(true))
+	assert.NoError(t, err)
+
+	// Not retried by default
+	gock.New(testURL).Post("/dataservice/url").Reply(400).BodyString(lockError)
+	_, err = client.Post("/url", "{}")
+	assert.Error(t, err)
+
+	// Lock wait timeout
+	client.LockWaitTimeout = 0
+	gock.New(testURL).Post("/dataservice/url").Reply(400).BodyString(lockError)
+	_, err = client.Post("/url", "{}", WaitForLock(true))
+	assert.Error(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientMaxConcurrentRequests tests the MaxConcurrentRequests modifier.
+func TestClientMaxConcurrentRequests(t *testing.T) {
+	defer gock.Off()
+	client, _ := NewClient(testURL, "usr", "pwd", true, MaxRetries(0), MaxConcurrentRequests(1))
+	gock.InterceptClient(client.HttpClient)
+	client.Token = "ABC"
+
+	// Slot available
+	gock.New(testURL).Get("/dataservice/url").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+
+	// All slots in use until the context is canceled
+	assert.NoError(t, client.acquireRequestSlot(context.Background(), 0))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = client.Get("/url", Context(ctx))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// Slot released
+	client.releaseRequestSlot()
+	gock.New(testURL).Get("/dataservice/url").Reply(200)
+	_, err = client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientGetData tests the Client::GetData method.
+func TestClientGetData(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/url").Reply(200).BodyString(`{"data":[{"a":1},{"a":2}]}`)
+	data, err := client.GetData("/url")
+	assert.NoError(t, err)
+	assert.Len(t, data, 2)
+	assert.Equal(t, int64(2), data[1].Get("a").Int())
+
+	// Absent data array
+	gock.New(testURL).Get("/dataservice/url").Reply(200).BodyString(`{}`)
+	data, err = client.GetData("/url")
+	assert.NoError(t, err)
+	assert.NotNil(t, data)
+	assert.Empty(t, data)
+}
+
+// TestClientExpectJSON tests the ExpectJSON modifier.
+func TestClientExpectJSON(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/url").Reply(200).SetHeader("Content-Type", "application/json;charset=UTF-8").BodyString(`{"a":1}`)
+	_, err := client.Get("/url", ExpectJSON(true))
+	assert.NoError(t, err)
+
+	gock.New(testURL).Get("/dataservice/url").Reply(200).SetHeader("Content-Type", "text/html").BodyString("<html>Login</html>")
+	_, err = client.Get("/url", ExpectJSON(true))
+	assert.True(t, errors.Is(err, ErrUnexpectedContentType))
+	assert.Contains(t, err.Error(), "<html>Login</html>")
+
+	// Empty response
+	gock.New(testURL).Get("/dataservice/url").Reply(204)
+	_, err = client.Get("/url", ExpectJSON(true))
+	assert.NoError(t, err)
+
+	// Not verified by default
+	gock.New(testURL).Get("/dataservice/url").Reply(200).SetHeader("Content-Type", "text/html").BodyString("<html>Login</html>")
+	_, err = client.Get("/url")
+	assert.NoError(t, err)
+}
+
+// TestJitter tests the jitter function.
+func TestJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		delay := jitter(10*time.Second, 0.2)
+		assert.GreaterOrEqual(t, delay, 8*time.Second)
+		assert.LessOrEqual(t, delay, 12*time.Second)
+	}
+	assert.Equal(t, 10*time.Second, jitter(10*time.Second, 0))
+}
+
+// TestClientPrefix tests the Prefix and NoPrefix modifiers.
+func TestClientPrefix(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/custom-api/url").Reply(200)
+	_, err := client.Get("/url", Prefix("/custom-api"))
+	assert.NoError(t, err)
+
+	gock.New(testURL).Get("/apidocs").Reply(200)
+	_, err = client.Get("/apidocs", NoPrefix)
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientPort tests the Port modifier.
+func TestClientPort(t *testing.T) {
+	client, err := NewClient("10.0.0.1", "usr", "pwd", true, Port(8443))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://10.0.0.1:8443", client.Url)
+
+	hosts := []string{"https://10.0.0.1:443", "https://10.0.0.2"}
+	client, err = NewClient("https://10.0.0.1:443", "usr", "pwd", true, Port(8443), Hosts(hosts))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://10.0.0.1:8443", client.Url)
+	assert.Equal(t, []string{"https://10.0.0.1:8443", "https://10.0.0.2:8443"}, client.Hosts)
+	assert.Equal(t, []string{"https://10.0.0.1:443", "https://10.0.0.2"}, hosts)
+
+	client, err = NewClient("[2001:db8::1]", "usr", "pwd", true, Port(8443))
+	assert.NoError(t, err)
+	assert.Equal(t, "https://[2001:db8::1]:8443", client.Url)
+
+	_, err = NewClient("10.0.0.1", "usr", "pwd", true, Port(65536))
+	assert.Error(t, err)
+}
+
+// TestSetDefaultClientOptions tests the SetDefaultClientOptions function.
+func TestSetDefaultClientOptions(t *testing.T) {
+	defer SetDefaultClientOptions()
+	SetDefaultClientOptions(MaxRetries(5), LockWaitTimeout(60))
+
+	client, _ := NewClient(testURL, "usr", "pwd", true, MaxRetries(1))
+	assert.Equal(t, 1, client.MaxRetries)
+	assert.Equal(t, 60, client.LockWaitTimeout)
+
+	SetDefaultClientOptions()
+	client, _ = NewClient(testURL, "usr", "pwd", true)
+	assert.Equal(t, DefaultMaxRetries, client.MaxRetries)
+}
+
+// TestClientURLRewrite tests the URLRewrite hook.
+func TestClientURLRewrite(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.URLRewrite = func(u *url.URL) {
+		u.Path = strings.Replace(u.Path, "/dataservice/", "/dataservice/tenant/T1/", 1)
+	}
+
+	gock.New(testURL).Get("/dataservice/tenant/T1/url").MatchParam("a", "1").Reply(200)
+	_, err := client.Get("/url?a=1")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientBodyTransform tests the BodyTransform modifier.
+func TestClientBodyTransform(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.BackoffMinDelay = 0
+	calls := 0
+	strip := func(body []byte) ([]byte, error) {
+		calls++
+		return []byte(Body{Str: string(body)}.Delete("readOnly").Str), nil
+	}
+
+	// transformed once, the same body is sent on retries
+	gock.New(testURL).Post("/dataservice/url").BodyString(`{"name":"a"}`).Reply(503)
+	gock.New(testURL).Post("/dataservice/url").BodyString(`{"name":"a"}`).Reply(200)
+	_, err := client.Post("/url", `{"name":"a","readOnly":true}`, BodyTransform(strip), Retries(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	assert.True(t, gock.IsDone())
+
+	// failing transformation, no request sent
+	_, err = client.Post("/url", `{}`, BodyTransform(func(body []byte) ([]byte, error) {
+		return nil, errors.New("transformation failed")
+	}))
+	assert.EqualError(t, err, "transformation failed")
+}
+
+// TestClientSingleflight tests the coalescing of identical GET requests.
+func TestClientSingleflight(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.Singleflight = true
+
+	gock.New(testURL).Get("/dataservice/device").Reply(200).Delay(100 * time.Millisecond).BodyString(`{"data":[]}`)
+	var wg sync.WaitGroup
+	errs := make([]error, 5)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = client.Get("/device")
+		}(i)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientGzipRequest tests the GzipRequest modifier.
+func TestClientGzipRequest(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	var bodies []string
+	var encodings []string
+	readBody := func(req *http.Request, _ *gock.Request) (bool, error) {
+		encodings = append(encodings, req.Header.Get("Content-Encoding"))
+		var reader io.Reader = req.Body
+		if req.Header.Get("Content-Encoding") == "gzip" {
+			gzipReader, err := gzip.NewReader(req.Body)
+			if err != nil {
+				return false, err
+			}
+			reader = gzipReader
+		}
+		body, err := io.ReadAll(reader)
+		bodies = append(bodies, string(body))
+		return true, err
+	}
+
+	gock.New(testURL).Post("/dataservice/url").AddMatcher(readBody).Reply(200)
+	_, err := client.Post("/url", `{"a":1}`, GzipRequest(true))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"gzip"}, encodings)
+	assert.Equal(t, []string{`{"a":1}`}, bodies)
+
+	// Fall back to an uncompressed body
+	bodies, encodings = nil, nil
+	gock.New(testURL).Post("/dataservice/url").AddMatcher(readBody).Reply(415)
+	gock.New(testURL).Post("/dataservice/url").AddMatcher(readBody).Reply(200)
+	_, err = client.Post("/url", `{"a":1}`, GzipRequest(true))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"gzip", ""}, encodings)
+	assert.Equal(t, []string{`{"a":1}`, `{"a":1}`}, bodies)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientGetFiltered tests the Client::GetFiltered method.
+func TestClientGetFiltered(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	body := `{"data":[{"host-name":"R1","reachability":"reachable"},{"host-name":"R2","reachability":"unreachable"}]}`
+
+	gock.New(testURL).Get("/dataservice/device").Reply(200).BodyString(body)
+	devices, err := client.GetFiltered("/device", `data.#(reachability=="reachable")#`)
+	assert.NoError(t, err)
+	assert.Len(t, devices, 1)
+	assert.Equal(t, "R1", devices[0].Get("host-name").String())
+
+	// Single match
+	gock.New(testURL).Get("/dataservice/device").Reply(200).BodyString(body)
+	devices, err = client.GetFiltered("/device", `data.#(host-name=="R2")`)
+	assert.NoError(t, err)
+	assert.Len(t, devices, 1)
+
+	// No match
+	gock.New(testURL).Get("/dataservice/device").Reply(200).BodyString(body)
+	devices, err = client.GetFiltered("/device", `data.#(host-name=="R3")`)
+	assert.NoError(t, err)
+	assert.Empty(t, devices)
+}
+
+// TestClientBeforeAttempt tests the BeforeAttempt hook.
+func TestClientBeforeAttempt(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.MaxRetries = 1
+	client.BackoffMinDelay = 0
+	client.BeforeAttempt = func(req *http.Request, attempt int) {
+		req.Header.Set("X-Nonce", strconv.Itoa(attempt))
+	}
+
+	gock.New(testURL).Get("/dataservice/url").MatchHeader("X-Nonce", "0").Reply(500)
+	gock.New(testURL).Get("/dataservice/url").MatchHeader("X-Nonce", "1").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientAuthExpiry tests the re-authentication of expired sessions.
+func TestClientAuthExpiry(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// 401 response
+	gock.New(testURL).Get("/dataservice/url").MatchHeader("X-XSRF-TOKEN", "ABC").Reply(401)
+	gock.New(testURL).Post("/j_security_check").Reply(200)
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("DEF")
+	gock.New(testURL).Get("/dataservice/url").MatchHeader("X-XSRF-TOKEN", "DEF").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+	assert.Equal(t, "DEF", client.Token)
+
+	// Login page returned instead of the response, only authenticated once
+	gock.New(testURL).Get("/dataservice/url").Reply(200).BodyString(`<html><form action="j_security_check"></form></html>`)
+	gock.New(testURL).Post("/j_security_check").Reply(200)
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("GHI")
+	gock.New(testURL).Get("/dataservice/url").Reply(403)
+	_, err = client.Get("/url")
+	assert.Error(t, err)
+	assert.Equal(t, 1, client.ReauthCount())
+
+	// Custom detector
+	client.AuthExpiryDetector = func(res Res, statusCode int) bool {
+		return false
+	}
+	gock.New(testURL).Get("/dataservice/url").Reply(401)
+	_, err = client.Get("/url")
+	assert.Error(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientTokenAsCookie tests the TokenAsCookie modifier.
+func TestClientTokenAsCookie(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.TokenAsCookie = true
+	matchTokenCookie := func(token string) func(req *http.Request, _ *gock.Request) (bool, error) {
+		return func(req *http.Request, _ *gock.Request) (bool, error) {
+			cookie, err := req.Cookie(TokenCookieName)
+			return err == nil && cookie.Value == token && req.Header.Get("X-XSRF-TOKEN") == token, nil
+		}
+	}
+
+	gock.New(testURL).Get("/dataservice/url").AddMatcher(matchTokenCookie("ABC")).Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+
+	// Token cookie updated after authenticating again
+	gock.New(testURL).Get("/dataservice/url").AddMatcher(matchTokenCookie("ABC")).Reply(401)
+	gock.New(testURL).Post("/j_security_check").Reply(200)
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("DEF")
+	gock.New(testURL).Get("/dataservice/url").AddMatcher(matchTokenCookie("DEF")).Reply(200)
+	_, err = client.Get("/url")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientXMLError tests the handling of XML error responses.
+func TestClientXMLError(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/url").Reply(400).SetHeader("Content-Type", "text/xml").
+		BodyString(`<?xml version="1.0"?><soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>` +
+			`<soap:Fault><faultcode>soap:Client</faultcode><faultstring>Invalid device ID</faultstring></soap:Fault>` +
+			`</soap:Body></soap:Envelope>`)
+	_, err := client.Get("/url")
+	assert.EqualError(t, err, "HTTP Request failed: StatusCode 400, XML error: Invalid device ID")
+
+	// Unknown XML structure
+	gock.New(testURL).Get("/dataservice/url").Reply(500).BodyString(`<?xml version="1.0"?><status>down</status>`)
+	_, err = client.Get("/url")
+	assert.ErrorIs(t, err, ErrMaxRetriesExceeded)
+	assert.Contains(t, err.Error(), `<status>down</status>`)
+
+	// Non-XML error
+	gock.New(testURL).Get("/dataservice/url").Reply(400).BodyString(`{"error":{"message":"Bad request"}}`)
+	_, err = client.Get("/url")
+	assert.EqualError(t, err, "HTTP Request failed: StatusCode 400")
+}
+
+// TestClientNoRetries tests that MaxRetries(0) results in exactly one attempt.
+func TestClientNoRetries(t *testing.T) {
+	defer gock.Off()
+	client, _ := NewClient(testURL, "usr", "pwd", true, MaxRetries(-1))
+	gock.InterceptClient(client.HttpClient)
+	client.Token = "ABC"
+	assert.Equal(t, 0, client.MaxRetries)
+
+	// Connection error
+	gock.New(testURL).Get("/dataservice/url").ReplyError(errors.New("connection refused"))
+	_, err := client.Get("/url")
+	assert.ErrorIs(t, err, ErrMaxRetriesExceeded)
+	assert.Contains(t, err.Error(), "connection refused")
+
+	// Server error
+	gock.New(testURL).Get("/dataservice/url").Reply(500)
+	_, err = client.Get("/url")
+	assert.ErrorIs(t, err, ErrMaxRetriesExceeded)
+	assert.NotContains(t, err.Error(), "gock")
+
+	// Rate limited
+	gock.New(testURL).Get("/dataservice/url").Reply(429).SetHeader("Retry-After", "60")
+	_, err = client.Get("/url")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientRetryAfterServerError tests the Retry-After header of 503 responses.
+func TestClientRetryAfterServerError(t *testing.T) {
+	defer gock.Off()
+	defer log.SetOutput(os.Stderr)
+	client := authenticatedTestClient()
+	client.RetryAfterJitter = 0
+	client.BackoffMinDelay = 60
+	client.BackoffMaxDelay = 60
+
+	// Retry-After preferred over the computed backoff
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	gock.New(testURL).Get("/dataservice/url").Reply(503).SetHeader("Retry-After", "0")
+	gock.New(testURL).Get("/dataservice/url").Reply(200)
+	_, err := client.Get("/url", Retries(1))
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "status_code=503 next_delay=0s")
+
+	// Retry-After capped at the maximum delay
+	client.BackoffMinDelay = 0
+	client.BackoffMaxDelay = 0
+	buf.Reset()
+	gock.New(testURL).Get("/dataservice/url").Reply(503).SetHeader("Retry-After", "3600")
+	gock.New(testURL).Get("/dataservice/url").Reply(200)
+	_, err = client.Get("/url", Retries(1))
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "status_code=503 next_delay=0s")
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientClock tests the time dependent logic using a fake clock.
+func TestClientClock(t *testing.T) {
+	defer gock.Off()
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	client := authenticatedTestClient()
+	withClock(clock)(&client)
+	client.RetryAfterJitter = 0
+
+	// Retry-After date relative to the client clock
+	gock.New(testURL).Get("/dataservice/url").Reply(429).SetHeader("Retry-After", clock.now.Add(time.Hour).Format(http.TimeFormat))
+	gock.New(testURL).Get("/dataservice/url").Reply(200)
+	_, err := client.Get("/url", Retries(1))
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Hour}, clock.slept)
+
+	// Session expiry and token age
+	gock.New(testURL).Post("/j_security_check").Reply(200).SetHeader("Set-Cookie", "JSESSIONID=XYZ; Max-Age=1800")
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("DEF")
+	client.Token = ""
+	assert.NoError(t, client.Authenticate())
+	assert.Equal(t, clock.now.Add(30*time.Minute), client.TokenExpiry())
+	clock.Sleep(31 * time.Minute)
+	assert.Equal(t, 31*time.Minute, client.TokenAge())
+	assert.True(t, client.tokenExpired())
+	assert.True(t, gock.IsDone())
+}
+
+// TestParseRetryAfter tests the parseRetryAfter function.
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	delay, ok := parseRetryAfter("120", now)
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, delay)
+	delay, ok = parseRetryAfter(now.Add(time.Hour).Format(http.TimeFormat), now)
+	assert.True(t, ok)
+	assert.Equal(t, time.Hour, delay)
+	_, ok = parseRetryAfter("", now)
+	assert.False(t, ok)
+	_, ok = parseRetryAfter("soon", now)
+	assert.False(t, ok)
+}
+
+// TestClientCorrelationID tests the correlation IDs of requests.
+func TestClientCorrelationID(t *testing.T) {
+	defer gock.Off()
+	defer log.SetOutput(os.Stderr)
+	client := authenticatedTestClient()
+	client.BackoffMinDelay = 0
+	client.CorrelationIDGenerator = func() string { return "ID1" }
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	gock.New(testURL).Get("/dataservice/url").Reply(503)
+	gock.New(testURL).Get("/dataservice/url").Reply(200)
+	res, err := client.Get("/url", Retries(1))
+	assert.NoError(t, err)
+	assert.Equal(t, "ID1", res.CorrelationID)
+	assert.Contains(t, buf.String(), "reason=server_error status_code=503 next_delay=0s correlation_id=ID1")
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		assert.Contains(t, line, "correlation_id=ID1")
+	}
+
+	// Correlation ID of the caller
+	gock.New(testURL).Get("/dataservice/url").Reply(200)
+	res, _ = client.Get("/url", CorrelationID("ID2"))
+	assert.Equal(t, "ID2", res.CorrelationID)
+
+	// Random UUID
+	client.CorrelationIDGenerator = nil
+	gock.New(testURL).Get("/dataservice/url").Reply(200)
+	res, _ = client.Get("/url")
+	assert.Regexp(t, "^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$", res.CorrelationID)
+}
+
+// BenchmarkClientGetParallel benchmarks concurrent GET requests against a local server.
+func BenchmarkClientGetParallel(b *testing.B) {
+	defer log.SetOutput(os.Stderr)
+	log.SetOutput(io.Discard)
+	body := `{"data":[` + strings.Repeat(`{"deviceId":"1.1.1.1","host-name":"router","reachability":"reachable"},`, 200) + `{}]}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, body)
+	}))
+	defer server.Close()
+	client, _ := NewClient(server.URL, "usr", "pwd", true)
+	client.Token = "ABC"
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := client.Get("/device"); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
+
+// TestReadAll tests that bodies read using pooled buffers are not shared.
+func TestReadAll(t *testing.T) {
+	first, err := readAll(strings.NewReader(`{"a":1}`))
+	assert.NoError(t, err)
+	second, err := readAll(strings.NewReader(`{"b":2}`))
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1}`, string(first))
+	assert.Equal(t, `{"b":2}`, string(second))
+	_, err = readAll(ErrReader{})
+	assert.Error(t, err)
+}
+
+// TestIsLoginPage tests the isLoginPage and isHTML functions.
+func TestIsLoginPage(t *testing.T) {
+	assert.True(t, isHTML([]byte(`<!DOCTYPE html><HTML><body></body></HTML>`)))
+	assert.False(t, isHTML([]byte(`{"html":"<b>bold</b>"}`)))
+	assert.False(t, isHTML([]byte(`<htm`)))
+	assert.False(t, isHTML(nil))
+
+	assert.True(t, isLoginPage([]byte(`<html><form method="POST" action="j_security_check"></form></html>`)))
+	assert.False(t, isLoginPage([]byte(`<html><body>503 Service Unavailable</body></html>`)))
+	assert.False(t, isLoginPage([]byte(`{"action":"j_security_check"}`)))
+}
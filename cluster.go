@@ -0,0 +1,151 @@
+package sdwan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// clusterPollInterval is the delay between two cluster health checks.
+const clusterPollInterval = 30 * time.Second
+
+// ServiceHealth is the health of a single service on a vManage cluster node.
+type ServiceHealth struct {
+	// Name is the name of the service, e.g. "application-server" or "configuration-db".
+	Name string
+	// Status is the status reported by vManage, e.g. "normal" or "running".
+	Status string
+	// Up indicates whether the service is up.
+	Up bool
+}
+
+// ClusterNodeHealth is the health of a single vManage cluster node.
+type ClusterNodeHealth struct {
+	// IP is the cluster IP address of the node.
+	IP string
+	// Hostname is the hostname of the node.
+	Hostname string
+	// Services are the services enabled on the node, sorted by name.
+	Services []ServiceHealth
+}
+
+// Healthy checks whether all services of the node are up.
+func (node ClusterNodeHealth) Healthy() bool {
+	return len(node.DownServices()) == 0
+}
+
+// DownServices returns the names of all services of the node which are not up.
+func (node ClusterNodeHealth) DownServices() []string {
+	var down []string
+	for _, service := range node.Services {
+		if !service.Up {
+			down = append(down, service.Name)
+		}
+	}
+	return down
+}
+
+// ClusterHealth is the health of all vManage cluster nodes.
+type ClusterHealth struct {
+	// Nodes are the nodes of the cluster.
+	Nodes []ClusterNodeHealth
+	// Res is the complete health details response.
+	Res Res
+}
+
+// Healthy checks whether the cluster has at least one node and all services on all nodes are up.
+func (health ClusterHealth) Healthy() bool {
+	if len(health.Nodes) == 0 {
+		return false
+	}
+	for _, node := range health.Nodes {
+		if !node.Healthy() {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseClusterHealth parses a response retrieved from /clusterManagement/health/details.
+// Depending on the release, the node details are nested in a "configJson" object and the status of a service
+// is either a string or an object with a "status" field.
+func ParseClusterHealth(res Res) ClusterHealth {
+	health := ClusterHealth{Res: res}
+	for _, item := range res.Get("data").Array() {
+		if item.Get("configJson").IsObject() {
+			item = item.Get("configJson")
+		}
+		node := ClusterNodeHealth{
+			IP:       item.Get("deviceIP").String(),
+			Hostname: item.Get("hostname").String(),
+		}
+		item.Get("services").ForEach(func(name, value gjson.Result) bool {
+			service := ServiceHealth{Name: name.String(), Status: value.String()}
+			if value.IsObject() {
+				service.Status = value.Get("status").String()
+			}
+			service.Up = serviceUp(service.Status) || value.Get("running").Bool()
+			node.Services = append(node.Services, service)
+			return true
+		})
+		sort.Slice(node.Services, func(i, j int) bool { return node.Services[i].Name < node.Services[j].Name })
+		health.Nodes = append(health.Nodes, node)
+	}
+	return health
+}
+
+// serviceUp checks whether a service status reported by vManage means the service is up.
+func serviceUp(status string) bool {
+	switch strings.ToLower(status) {
+	case "normal", "running", "up", "true":
+		return true
+	}
+	return false
+}
+
+// GetClusterHealth retrieves the health of all services on all vManage cluster nodes.
+func (client *Client) GetClusterHealth(mods ...func(*Req)) (ClusterHealth, error) {
+	res, err := client.Get("/clusterManagement/health/details", mods...)
+	if err != nil {
+		return ClusterHealth{Res: res}, err
+	}
+	return ParseClusterHealth(res), nil
+}
+
+// WaitForClusterHealthy polls the cluster health until all services on all nodes are up or the timeout expires,
+// e.g. before starting an upgrade. If the timeout expires, an error wrapping ErrClusterTimeout and naming the
+// services which are down is returned along with the last health.
+func (client *Client) WaitForClusterHealthy(timeout time.Duration, mods ...func(*Req)) (ClusterHealth, error) {
+	var health ClusterHealth
+	down := []string{}
+	err := client.poll(context.Background(), client.clockNow().Add(timeout), clusterPollInterval, func() (bool, error) {
+		var err error
+		health, err = client.GetClusterHealth(mods...)
+		if err != nil {
+			return false, err
+		}
+		if health.Healthy() {
+			log.Printf("[DEBUG] vManage cluster healthy")
+			return true, nil
+		}
+		down = []string{}
+		for _, node := range health.Nodes {
+			for _, service := range node.DownServices() {
+				down = append(down, node.IP+"/"+service)
+			}
+		}
+		log.Printf("[DEBUG] Waiting for vManage cluster, services down: %s", strings.Join(down, ", "))
+		return false, nil
+	})
+	if errors.Is(err, errPollDeadline) {
+		log.Printf("[ERROR] vManage cluster not healthy after %v, services down: %s", timeout, strings.Join(down, ", "))
+		return health, fmt.Errorf("%w, services down: %s", ErrClusterTimeout, strings.Join(down, ", "))
+	}
+	return health, err
+}
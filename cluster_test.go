@@ -0,0 +1,47 @@
+package sdwan
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestParseClusterHealth tests the ParseClusterHealth function.
+func TestParseClusterHealth(t *testing.T) {
+	health := ParseClusterHealth(newRes([]byte(`{"data":[
+		{"configJson":{"deviceIP":"10.0.0.1","hostname":"vm1","services":{"statistics-db":{"status":"normal"},"application-server":{"status":"normal"}}}},
+		{"deviceIP":"10.0.0.2","hostname":"vm2","services":{"configuration-db":"stopped","messaging-server":"running"}}
+	]}`)))
+	assert.Len(t, health.Nodes, 2)
+	assert.Equal(t, "vm1", health.Nodes[0].Hostname)
+	assert.Equal(t, "application-server", health.Nodes[0].Services[0].Name)
+	assert.True(t, health.Nodes[0].Healthy())
+	assert.Equal(t, []string{"configuration-db"}, health.Nodes[1].DownServices())
+	assert.False(t, health.Healthy())
+	assert.False(t, ParseClusterHealth(newRes([]byte(`{"data":[]}`))).Healthy())
+}
+
+// TestClientWaitForClusterHealthy tests the Client::WaitForClusterHealthy method.
+func TestClientWaitForClusterHealthy(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	withClock(&fakeClock{now: time.Now()})(&client)
+
+	gock.New(testURL).Get("/dataservice/clusterManagement/health/details").Reply(200).
+		BodyString(`{"data":[{"deviceIP":"10.0.0.1","services":{"configuration-db":"stopped"}}]}`)
+	gock.New(testURL).Get("/dataservice/clusterManagement/health/details").Reply(200).
+		BodyString(`{"data":[{"deviceIP":"10.0.0.1","services":{"configuration-db":"normal"}}]}`)
+	health, err := client.WaitForClusterHealthy(time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, health.Healthy())
+
+	gock.New(testURL).Get("/dataservice/clusterManagement/health/details").Reply(200).
+		BodyString(`{"data":[{"deviceIP":"10.0.0.1","services":{"configuration-db":"stopped"}}]}`)
+	_, err = client.WaitForClusterHealthy(0)
+	assert.True(t, errors.Is(err, ErrClusterTimeout))
+	assert.Contains(t, err.Error(), "10.0.0.1/configuration-db")
+	assert.True(t, gock.IsDone())
+}
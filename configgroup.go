@@ -0,0 +1,38 @@
+package sdwan
+
+import (
+	"fmt"
+	"time"
+)
+
+// CreateConfigGroup creates a new configuration group and returns the response containing its "id".
+func (client *Client) CreateConfigGroup(data string, mods ...func(*Req)) (Res, error) {
+	return client.Post("/v1/config-group", data, mods...)
+}
+
+// GetConfigGroup retrieves a configuration group by its ID.
+func (client *Client) GetConfigGroup(groupID string, mods ...func(*Req)) (Res, error) {
+	return client.Get("/v1/config-group/"+groupID, mods...)
+}
+
+// DeleteConfigGroup deletes a configuration group by its ID.
+func (client *Client) DeleteConfigGroup(groupID string, mods ...func(*Req)) (Res, error) {
+	return client.Delete("/v1/config-group/"+groupID, mods...)
+}
+
+// DeployConfigGroup deploys a configuration group to the given devices and waits for the resulting task to complete.
+func (client *Client) DeployConfigGroup(groupID string, deviceIDs []string, timeout time.Duration, mods ...func(*Req)) (TaskResult, error) {
+	body := Body{}.SetRaw("devices", "[]")
+	for _, id := range deviceIDs {
+		body = body.SetRaw("devices.-1", Body{}.Set("id", id).Str)
+	}
+	res, err := client.Post("/v1/config-group/"+groupID+"/device/deploy", body.Str, mods...)
+	if err != nil {
+		return TaskResult{Res: res}, err
+	}
+	taskID := res.Get("parentTaskId").String()
+	if taskID == "" {
+		return TaskResult{Res: res}, fmt.Errorf("%w: no parentTaskId in config group deploy response", ErrUnexpectedSchema)
+	}
+	return client.WaitForTask(taskID, timeout, mods...)
+}
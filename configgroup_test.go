@@ -0,0 +1,30 @@
+package sdwan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientDeployConfigGroup tests the Client::DeployConfigGroup method.
+func TestClientDeployConfigGroup(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).
+		Post("/dataservice/v1/config-group/G1/device/deploy").
+		BodyString(`{"devices":[{"id":"D1"}]}`).
+		Reply(200).
+		BodyString(`{"parentTaskId":"T1"}`)
+	gock.New(testURL).Get("/dataservice/device/action/status/T1").Reply(200).BodyString(`{"summary":{"status":"done"}}`)
+	_, err := client.DeployConfigGroup("G1", []string{"D1"}, time.Minute)
+	assert.NoError(t, err)
+
+	// Missing task ID
+	gock.New(testURL).Post("/dataservice/v1/config-group/G1/device/deploy").Reply(200).BodyString(`{}`)
+	_, err = client.DeployConfigGroup("G1", []string{"D1"}, time.Minute)
+	assert.ErrorIs(t, err, ErrUnexpectedSchema)
+	assert.True(t, gock.IsDone())
+}
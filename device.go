@@ -0,0 +1,321 @@
+package sdwan
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// GetDeviceRunningConfig retrieves the running configuration of a device, e.g. to create a configuration snapshot.
+// vManage returns the configuration of CLI-mode devices as "config" attribute, whereas the configuration of
+// vManage-mode devices is wrapped in a "data" array. Both are returned as plain configuration text.
+//
+//	config, err := client.GetDeviceRunningConfig("C8K-12345678-ABCD-EFGH-IJKL-123456789012")
+func (client *Client) GetDeviceRunningConfig(deviceID string, mods ...func(*Req)) (string, error) {
+	res, err := client.Get("/template/config/running/"+deviceID, mods...)
+	if err != nil {
+		return "", err
+	}
+	if config := res.Get("config"); config.Exists() {
+		return config.String(), nil
+	}
+	if config := res.Get("data.0.config"); config.Exists() {
+		return config.String(), nil
+	}
+	if !res.IsObject() && !res.IsArray() && !res.IsEmpty() {
+		// some releases return the configuration as plain text
+		return string(res.Bytes()), nil
+	}
+	return "", fmt.Errorf("running configuration of device %s: %w", deviceID, ErrNotFound)
+}
+
+// AttachCLITemplate pushes a device specific CLI configuration to a device and waits for the resulting task to complete.
+// ErrDeviceInVManageMode is returned if the device is managed by a (feature) device template, in this case
+// the device has to be detached from its template first.
+func (client *Client) AttachCLITemplate(deviceID, config string, timeout time.Duration, mods ...func(*Req)) error {
+	body := Body{}.
+		Set("deviceId", deviceID).
+		SetRaw("isEdited", "true").
+		SetRaw("isMasterEdited", "false").
+		SetRaw("isDraftDisabled", "false").
+		Set("template", config)
+	res, err := client.Post("/template/config/attach", body.Str, mods...)
+	if err != nil {
+		message := strings.ToLower(res.Get("error.message").String() + " " + res.Get("error.details").String())
+		if strings.Contains(message, "vmanage mode") {
+			return fmt.Errorf("device %s: %w", deviceID, ErrDeviceInVManageMode)
+		}
+		return err
+	}
+	_, err = client.WaitForTask(res.Get("id").String(), timeout, mods...)
+	return err
+}
+
+// getEdge retrieves the inventory entry of a WAN edge device.
+func (client *Client) getEdge(deviceID string, mods ...func(*Req)) (Res, error) {
+	res, err := client.Get("/system/device/vedges?uuid="+url.QueryEscape(deviceID), mods...)
+	if err != nil {
+		return res, err
+	}
+	edge := res.Get("data.0")
+	if !edge.Exists() {
+		return res, fmt.Errorf("device %s: %w", deviceID, ErrNotFound)
+	}
+	return toRes(edge), nil
+}
+
+// GetAttachedTemplate retrieves the ID of the device template attached to a device.
+// An empty ID is returned if the device is not attached to a template, i.e. it is in CLI mode.
+func (client *Client) GetAttachedTemplate(deviceID string, mods ...func(*Req)) (string, error) {
+	edge, err := client.getEdge(deviceID, mods...)
+	if err != nil {
+		return "", err
+	}
+	return edge.Get("templateId").String(), nil
+}
+
+// DetachDevice detaches a device from its device template, which moves the device to CLI mode,
+// and waits for the resulting task to complete. This is required before attaching a CLI configuration
+// (see AttachCLITemplate) or another template to the device.
+func (client *Client) DetachDevice(deviceID string, timeout time.Duration, mods ...func(*Req)) error {
+	edge, err := client.getEdge(deviceID, mods...)
+	if err != nil {
+		return err
+	}
+	device := Body{}.
+		Set("deviceId", deviceID).
+		Set("deviceIP", edge.Get("system-ip").String())
+	body := Body{}.
+		Set("deviceType", "vedge").
+		SetRaw("devices", "[]").
+		SetRaw("devices.-1", device.Str)
+	res, err := client.Post("/template/config/device/mode/cli", body.Str, mods...)
+	if err != nil {
+		return err
+	}
+	_, err = client.WaitForTask(res.Get("id").String(), timeout, mods...)
+	return err
+}
+
+// Types of configuration diff lines.
+const (
+	ConfigLineContext = "context"
+	ConfigLineAdded   = "added"
+	ConfigLineRemoved = "removed"
+)
+
+// ConfigDiffLine is a single line of a configuration diff.
+type ConfigDiffLine struct {
+	// Type is ConfigLineContext, ConfigLineAdded (only part of the intended configuration)
+	// or ConfigLineRemoved (only part of the running configuration).
+	Type string
+	// Text is the configuration line.
+	Text string
+}
+
+// GetConfigDiff retrieves the intended and running configuration of a device and returns the line diff
+// from the running to the intended configuration, e.g. to detect configuration drift.
+// The diff is empty if both configurations are identical.
+func (client *Client) GetConfigDiff(deviceID string, mods ...func(*Req)) ([]ConfigDiffLine, error) {
+	res, err := client.Get("/template/config/diff/"+deviceID, mods...)
+	if err != nil {
+		return nil, err
+	}
+	running := res.Get("runningConfig")
+	intended := res.Get("intendConfig")
+	if !running.Exists() || !intended.Exists() {
+		return nil, fmt.Errorf("configuration diff of device %s: %w", deviceID, ErrNotFound)
+	}
+	lines := diffLines(splitLines(running.String()), splitLines(intended.String()))
+	for _, line := range lines {
+		if line.Type != ConfigLineContext {
+			return lines, nil
+		}
+	}
+	return []ConfigDiffLine{}, nil
+}
+
+// splitLines splits a configuration into lines, trailing empty lines are removed.
+func splitLines(config string) []string {
+	lines := strings.Split(strings.ReplaceAll(config, "\r\n", "\n"), "\n")
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines calculates the line diff between two configurations based on their longest common subsequence.
+// Common leading and trailing lines are trimmed first, the remaining lines are compared using Hirschberg's
+// algorithm, which requires memory linear in the number of lines.
+func diffLines(from, to []string) []ConfigDiffLine {
+	lines := []ConfigDiffLine{}
+	prefix := 0
+	for prefix < len(from) && prefix < len(to) && from[prefix] == to[prefix] {
+		lines = append(lines, ConfigDiffLine{Type: ConfigLineContext, Text: from[prefix]})
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(from)-prefix && suffix < len(to)-prefix && from[len(from)-1-suffix] == to[len(to)-1-suffix] {
+		suffix++
+	}
+	lines = diffMiddle(from[prefix:len(from)-suffix], to[prefix:len(to)-suffix], lines)
+	for _, line := range from[len(from)-suffix:] {
+		lines = append(lines, ConfigDiffLine{Type: ConfigLineContext, Text: line})
+	}
+	return lines
+}
+
+// diffMiddle appends the line diff of two configurations to lines, recursively splitting the configurations
+// at a point of their longest common subsequence (Hirschberg's algorithm).
+func diffMiddle(from, to []string, lines []ConfigDiffLine) []ConfigDiffLine {
+	switch {
+	case len(from) == 0:
+		for _, line := range to {
+			lines = append(lines, ConfigDiffLine{Type: ConfigLineAdded, Text: line})
+		}
+		return lines
+	case len(to) == 0:
+		for _, line := range from {
+			lines = append(lines, ConfigDiffLine{Type: ConfigLineRemoved, Text: line})
+		}
+		return lines
+	case len(from) == 1:
+		for j, line := range to {
+			if line == from[0] {
+				lines = diffMiddle(nil, to[:j], lines)
+				lines = append(lines, ConfigDiffLine{Type: ConfigLineContext, Text: line})
+				return diffMiddle(nil, to[j+1:], lines)
+			}
+		}
+		lines = diffMiddle(nil, to, lines)
+		return diffMiddle(from, nil, lines)
+	}
+	mid := len(from) / 2
+	forward := lcsForward(from[:mid], to)
+	backward := lcsBackward(from[mid:], to)
+	split, best := 0, -1
+	for j := range forward {
+		if forward[j]+backward[j] > best {
+			split, best = j, forward[j]+backward[j]
+		}
+	}
+	lines = diffMiddle(from[:mid], to[:split], lines)
+	return diffMiddle(from[mid:], to[split:], lines)
+}
+
+// lcsForward returns the lengths of the longest common subsequences of from and every prefix to[:j].
+func lcsForward(from, to []string) []int {
+	prev := make([]int, len(to)+1)
+	cur := make([]int, len(to)+1)
+	for i := range from {
+		for j := 1; j <= len(to); j++ {
+			if from[i] == to[j-1] {
+				cur[j] = prev[j-1] + 1
+			} else if prev[j] >= cur[j-1] {
+				cur[j] = prev[j]
+			} else {
+				cur[j] = cur[j-1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// lcsBackward returns the lengths of the longest common subsequences of from and every suffix to[j:].
+func lcsBackward(from, to []string) []int {
+	prev := make([]int, len(to)+1)
+	cur := make([]int, len(to)+1)
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if from[i] == to[j] {
+				cur[j] = prev[j+1] + 1
+			} else if prev[j] >= cur[j+1] {
+				cur[j] = prev[j]
+			} else {
+				cur[j] = cur[j+1]
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev
+}
+
+// BFDSession is a BFD session of a device.
+type BFDSession struct {
+	// LocalSystemIP is the system IP of the device.
+	LocalSystemIP string
+	// RemoteSystemIP is the system IP of the peer.
+	RemoteSystemIP string
+	// LocalColor is the TLOC color of the device.
+	LocalColor string
+	// RemoteColor is the TLOC color of the peer.
+	RemoteColor string
+	// State is the session state, e.g. "up".
+	State string
+	// Uptime is the session uptime as reported by the device, e.g. "0:02:10:43".
+	Uptime string
+	// Res is the complete session.
+	Res Res
+}
+
+// ControlConnection is a control connection of a device to a controller or another device.
+type ControlConnection struct {
+	// LocalSystemIP is the system IP of the device.
+	LocalSystemIP string
+	// RemoteSystemIP is the system IP of the peer.
+	RemoteSystemIP string
+	// PeerType is the type of the peer, e.g. "vsmart" or "vmanage".
+	PeerType string
+	// LocalColor is the TLOC color of the device.
+	LocalColor string
+	// State is the connection state, e.g. "up".
+	State string
+	// Uptime is the connection uptime as reported by the device, e.g. "0:02:10:43".
+	Uptime string
+	// Res is the complete connection.
+	Res Res
+}
+
+// GetBFDSessions retrieves the real-time BFD sessions of a device identified by its system IP.
+func (client *Client) GetBFDSessions(deviceID string, mods ...func(*Req)) ([]BFDSession, error) {
+	data, err := client.GetData("/device/bfd/sessions?deviceId="+url.QueryEscape(deviceID), mods...)
+	if err != nil {
+		return nil, err
+	}
+	sessions := []BFDSession{}
+	for _, session := range data {
+		sessions = append(sessions, BFDSession{
+			LocalSystemIP:  session.Get("vdevice-name").String(),
+			RemoteSystemIP: session.Get("system-ip").String(),
+			LocalColor:     session.Get("local-color").String(),
+			RemoteColor:    session.Get("color").String(),
+			State:          session.Get("state").String(),
+			Uptime:         session.Get("uptime").String(),
+			Res:            session,
+		})
+	}
+	return sessions, nil
+}
+
+// GetControlConnections retrieves the real-time control connections of a device identified by its system IP.
+func (client *Client) GetControlConnections(deviceID string, mods ...func(*Req)) ([]ControlConnection, error) {
+	data, err := client.GetData("/device/control/connections?deviceId="+url.QueryEscape(deviceID), mods...)
+	if err != nil {
+		return nil, err
+	}
+	connections := []ControlConnection{}
+	for _, connection := range data {
+		connections = append(connections, ControlConnection{
+			LocalSystemIP:  connection.Get("vdevice-name").String(),
+			RemoteSystemIP: connection.Get("system-ip").String(),
+			PeerType:       connection.Get("peer-type").String(),
+			LocalColor:     connection.Get("local-color").String(),
+			State:          connection.Get("state").String(),
+			Uptime:         connection.Get("uptime").String(),
+			Res:            connection,
+		})
+	}
+	return connections, nil
+}
@@ -0,0 +1,229 @@
+package sdwan
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientGetDeviceRunningConfig tests the Client::GetDeviceRunningConfig method.
+func TestClientGetDeviceRunningConfig(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// CLI-mode device
+	gock.New(testURL).Get("/dataservice/template/config/running/DEV1").Reply(200).BodyString(`{"config":"hostname R1\n"}`)
+	config, err := client.GetDeviceRunningConfig("DEV1")
+	assert.NoError(t, err)
+	assert.Equal(t, "hostname R1\n", config)
+
+	// vManage-mode device
+	gock.New(testURL).Get("/dataservice/template/config/running/DEV2").Reply(200).BodyString(`{"data":[{"config":"hostname R2\n"}]}`)
+	config, err = client.GetDeviceRunningConfig("DEV2")
+	assert.NoError(t, err)
+	assert.Equal(t, "hostname R2\n", config)
+
+	// Plain text response
+	gock.New(testURL).Get("/dataservice/template/config/running/DEV3").Reply(200).BodyString("hostname R3\n")
+	config, err = client.GetDeviceRunningConfig("DEV3")
+	assert.NoError(t, err)
+	assert.Equal(t, "hostname R3\n", config)
+
+	// Missing configuration
+	gock.New(testURL).Get("/dataservice/template/config/running/DEV4").Reply(200).BodyString(`{"data":[]}`)
+	_, err = client.GetDeviceRunningConfig("DEV4")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientAttachCLITemplate tests the Client::AttachCLITemplate method.
+func TestClientAttachCLITemplate(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	withClock(&fakeClock{now: time.Now()})(&client)
+
+	gock.New(testURL).
+		Post("/dataservice/template/config/attach").
+		BodyString(`{"deviceId":"DEV1","isEdited":true,"isMasterEdited":false,"isDraftDisabled":false,"template":"hostname R1\n"}`).
+		Reply(200).
+		BodyString(`{"id":"T1"}`)
+	gock.New(testURL).Get("/dataservice/device/action/status/T1").Reply(200).BodyString(`{"summary":{"status":"done"}}`)
+	err := client.AttachCLITemplate("DEV1", "hostname R1\n", time.Minute)
+	assert.NoError(t, err)
+
+	// Device in vManage mode
+	gock.New(testURL).Post("/dataservice/template/config/attach").Reply(400).
+		BodyString(`{"error":{"message":"Failed to attach","details":"Device is in vManage mode"}}`)
+	err = client.AttachCLITemplate("DEV1", "hostname R1\n", time.Minute)
+	assert.True(t, errors.Is(err, ErrDeviceInVManageMode))
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientGetAttachedTemplate tests the Client::GetAttachedTemplate method.
+func TestClientGetAttachedTemplate(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV1").Reply(200).
+		BodyString(`{"data":[{"uuid":"DEV1","templateId":"TPL1"}]}`)
+	id, err := client.GetAttachedTemplate("DEV1")
+	assert.NoError(t, err)
+	assert.Equal(t, "TPL1", id)
+
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV2").Reply(200).BodyString(`{"data":[]}`)
+	_, err = client.GetAttachedTemplate("DEV2")
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientDetachDevice tests the Client::DetachDevice method.
+func TestClientDetachDevice(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	withClock(&fakeClock{now: time.Now()})(&client)
+
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV1").Reply(200).
+		BodyString(`{"data":[{"uuid":"DEV1","system-ip":"1.1.1.1","templateId":"TPL1"}]}`)
+	gock.New(testURL).Post("/dataservice/template/config/device/mode/cli").
+		BodyString(`{"deviceType":"vedge","devices":[{"deviceId":"DEV1","deviceIP":"1.1.1.1"}]}`).
+		Reply(200).BodyString(`{"id":"T1"}`)
+	gock.New(testURL).Get("/dataservice/device/action/status/T1").Reply(200).BodyString(`{"summary":{"status":"done"}}`)
+	assert.NoError(t, client.DetachDevice("DEV1", time.Minute))
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientGetConfigDiff tests the Client::GetConfigDiff method.
+func TestClientGetConfigDiff(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/template/config/diff/DEV1").Reply(200).
+		BodyString(`{"runningConfig":"hostname R1\nntp server 1.1.1.1\n!\n","intendConfig":"hostname R1\nntp server 2.2.2.2\n!\n"}`)
+	diff, err := client.GetConfigDiff("DEV1")
+	assert.NoError(t, err)
+	assert.Equal(t, []ConfigDiffLine{
+		{Type: ConfigLineContext, Text: "hostname R1"},
+		{Type: ConfigLineAdded, Text: "ntp server 2.2.2.2"},
+		{Type: ConfigLineRemoved, Text: "ntp server 1.1.1.1"},
+		{Type: ConfigLineContext, Text: "!"},
+	}, diff)
+
+	// No drift
+	gock.New(testURL).Get("/dataservice/template/config/diff/DEV1").Reply(200).
+		BodyString(`{"runningConfig":"hostname R1\n","intendConfig":"hostname R1"}`)
+	diff, err = client.GetConfigDiff("DEV1")
+	assert.NoError(t, err)
+	assert.Empty(t, diff)
+
+	// Unexpected response
+	gock.New(testURL).Get("/dataservice/template/config/diff/DEV1").Reply(200).BodyString(`{}`)
+	_, err = client.GetConfigDiff("DEV1")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+// TestDiffLines tests the diffLines function against the full longest common subsequence table.
+func TestDiffLines(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randomLines := func() []string {
+		lines := make([]string, rnd.Intn(12))
+		for i := range lines {
+			lines[i] = string(rune('a' + rnd.Intn(4)))
+		}
+		return lines
+	}
+	for n := 0; n < 500; n++ {
+		from, to := randomLines(), randomLines()
+		lcs := make([][]int, len(from)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(to)+1)
+		}
+		for i := len(from) - 1; i >= 0; i-- {
+			for j := len(to) - 1; j >= 0; j-- {
+				if from[i] == to[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else if lcs[i+1][j] > lcs[i][j+1] {
+					lcs[i][j] = lcs[i+1][j]
+				} else {
+					lcs[i][j] = lcs[i][j+1]
+				}
+			}
+		}
+		var gotFrom, gotTo []string
+		context := 0
+		for _, line := range diffLines(from, to) {
+			if line.Type != ConfigLineAdded {
+				gotFrom = append(gotFrom, line.Text)
+			}
+			if line.Type != ConfigLineRemoved {
+				gotTo = append(gotTo, line.Text)
+			}
+			if line.Type == ConfigLineContext {
+				context++
+			}
+		}
+		assert.Equal(t, strings.Join(from, "\n"), strings.Join(gotFrom, "\n"))
+		assert.Equal(t, strings.Join(to, "\n"), strings.Join(gotTo, "\n"))
+		assert.Equal(t, lcs[0][0], context)
+	}
+}
+
+// TestDiffLinesLarge tests that large configurations are compared in linear memory.
+func TestDiffLinesLarge(t *testing.T) {
+	from := make([]string, 5000)
+	to := make([]string, 5000)
+	for i := range from {
+		from[i] = fmt.Sprintf("interface GigabitEthernet%d", i)
+		to[i] = from[i]
+		if i%100 == 0 {
+			to[i] = fmt.Sprintf("description changed %d", i)
+		}
+	}
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	lines := diffLines(from, to)
+	runtime.ReadMemStats(&after)
+	added := 0
+	for _, line := range lines {
+		if line.Type == ConfigLineAdded {
+			added++
+		}
+	}
+	assert.Equal(t, 50, added)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(32<<20))
+}
+
+// TestClientGetBFDSessions tests the Client::GetBFDSessions method.
+func TestClientGetBFDSessions(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/device/bfd/sessions").MatchParam("deviceId", "1.1.1.1").Reply(200).
+		BodyString(`{"data":[{"vdevice-name":"1.1.1.1","system-ip":"2.2.2.2","local-color":"mpls","color":"biz-internet","state":"up","uptime":"0:01:00:00"}]}`)
+	sessions, err := client.GetBFDSessions("1.1.1.1")
+	assert.NoError(t, err)
+	assert.Len(t, sessions, 1)
+	assert.Equal(t, "2.2.2.2", sessions[0].RemoteSystemIP)
+	assert.Equal(t, "biz-internet", sessions[0].RemoteColor)
+	assert.Equal(t, "up", sessions[0].State)
+}
+
+// TestClientGetControlConnections tests the Client::GetControlConnections method.
+func TestClientGetControlConnections(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/device/control/connections").MatchParam("deviceId", "1.1.1.1").Reply(200).
+		BodyString(`{"data":[{"vdevice-name":"1.1.1.1","system-ip":"3.3.3.3","peer-type":"vsmart","local-color":"mpls","state":"up","uptime":"0:02:00:00"}]}`)
+	connections, err := client.GetControlConnections("1.1.1.1")
+	assert.NoError(t, err)
+	assert.Len(t, connections, 1)
+	assert.Equal(t, "vsmart", connections[0].PeerType)
+	assert.Equal(t, "0:02:00:00", connections[0].Uptime)
+}
@@ -0,0 +1,109 @@
+package sdwan
+
+import (
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// Types of differences reported by DiffRes.
+const (
+	FieldAdded   = "added"
+	FieldRemoved = "removed"
+	FieldChanged = "changed"
+)
+
+// FieldDiff is a difference of a single leaf value between two responses.
+type FieldDiff struct {
+	// Path is the path of the leaf value, e.g. "data.0.host-name".
+	Path string
+	// Type is FieldAdded, FieldRemoved or FieldChanged.
+	Type string
+	// Old is the value of the first response, it does not exist if the value has been added.
+	Old Res
+	// New is the value of the second response, it does not exist if the value has been removed.
+	New Res
+}
+
+// DiffRes compares the leaf values of two responses and returns all added, removed and changed paths,
+// e.g. to check whether a live configuration matches the desired configuration. Paths matching one of
+// ignorePaths are skipped, including all paths below them. A "*" segment matches any key or array index,
+// e.g. "data.*.lastUpdated". Object keys are compared regardless of their order, array elements by index.
+func DiffRes(a, b Res, ignorePaths []string) []FieldDiff {
+	var diffs []FieldDiff
+	newValues := map[string]gjson.Result{}
+	walkLeaves(b.Result, "", func(path string, value gjson.Result) {
+		newValues[path] = value
+	})
+	oldPaths := map[string]bool{}
+	walkLeaves(a.Result, "", func(path string, value gjson.Result) {
+		oldPaths[path] = true
+		if ignoredPath(path, ignorePaths) {
+			return
+		}
+		newValue, ok := newValues[path]
+		if !ok {
+			diffs = append(diffs, FieldDiff{Path: path, Type: FieldRemoved, Old: toRes(value)})
+		} else if !equalValues(value, newValue) {
+			diffs = append(diffs, FieldDiff{Path: path, Type: FieldChanged, Old: toRes(value), New: toRes(newValue)})
+		}
+	})
+	walkLeaves(b.Result, "", func(path string, value gjson.Result) {
+		if !oldPaths[path] && !ignoredPath(path, ignorePaths) {
+			diffs = append(diffs, FieldDiff{Path: path, Type: FieldAdded, New: toRes(value)})
+		}
+	})
+	return diffs
+}
+
+// equalValues compares two leaf values, numbers are compared by value, e.g. 1 equals 1.0.
+func equalValues(a, b gjson.Result) bool {
+	if a.Type != b.Type {
+		return false
+	}
+	if a.Type == gjson.Number {
+		return a.Num == b.Num
+	}
+	return a.Raw == b.Raw
+}
+
+// ignoredPath checks whether a path equals or is below one of the ignored paths.
+func ignoredPath(path string, ignorePaths []string) bool {
+	segments := splitPath(path)
+	for _, ignorePath := range ignorePaths {
+		ignoreSegments := splitPath(ignorePath)
+		if len(ignoreSegments) > len(segments) {
+			continue
+		}
+		match := true
+		for i, segment := range ignoreSegments {
+			if segment != "*" && segment != segments[i] {
+				match = false
+				break
+			}
+		}
+		if match {
+			return true
+		}
+	}
+	return false
+}
+
+// splitPath splits a path into its segments, escaped dots are not treated as separators.
+func splitPath(path string) []string {
+	var segments []string
+	var segment strings.Builder
+	for i := 0; i < len(path); i++ {
+		switch {
+		case path[i] == '\\' && i+1 < len(path):
+			i++
+			segment.WriteByte(path[i])
+		case path[i] == '.':
+			segments = append(segments, segment.String())
+			segment.Reset()
+		default:
+			segment.WriteByte(path[i])
+		}
+	}
+	return append(segments, segment.String())
+}
@@ -0,0 +1,28 @@
+package sdwan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDiffRes tests the DiffRes function.
+func TestDiffRes(t *testing.T) {
+	a := newRes([]byte(`{"name":"R1","mtu":1500,"tags":["a","b"],"data":[{"id":"1","lastUpdated":1}],"old":true}`))
+	b := newRes([]byte(`{"tags":["a","c"],"name":"R1","mtu":1500.0,"data":[{"id":"2","lastUpdated":2}],"new":{}}`))
+
+	diffs := DiffRes(a, b, []string{"data.*.lastUpdated"})
+	assert.Len(t, diffs, 4)
+	assert.Equal(t, FieldDiff{Path: "tags.1", Type: FieldChanged, Old: toRes(a.Get("tags.1")), New: toRes(b.Get("tags.1"))}, diffs[0])
+	assert.Equal(t, "data.0.id", diffs[1].Path)
+	assert.Equal(t, "2", diffs[1].New.String())
+	assert.Equal(t, FieldDiff{Path: "old", Type: FieldRemoved, Old: toRes(a.Get("old"))}, diffs[2])
+	assert.Equal(t, "new", diffs[3].Path)
+	assert.Equal(t, FieldAdded, diffs[3].Type)
+
+	// Ignored subtree
+	assert.Len(t, DiffRes(a, b, []string{"tags", "data", "old", "new"}), 0)
+
+	// Equal responses
+	assert.Empty(t, DiffRes(a, a, nil))
+}
@@ -0,0 +1,69 @@
+package sdwan
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrAuthFailed is returned when authentication against vManage fails.
+var ErrAuthFailed = errors.New("authentication failed")
+
+// ErrInvalidCredentials is returned when vManage rejects the provided username or password.
+var ErrInvalidCredentials = fmt.Errorf("%w, invalid credentials", ErrAuthFailed)
+
+// ErrTokenRetrieval is returned when the XSRF token cannot be retrieved after a successful login.
+var ErrTokenRetrieval = fmt.Errorf("%w, token retrieval", ErrAuthFailed)
+
+// ErrRateLimited is returned when vManage keeps rate limiting a request (HTTP status code 429).
+var ErrRateLimited = errors.New("HTTP Request rate limited")
+
+// ErrMaxRetriesExceeded is returned when a request still fails after all retries have been exhausted.
+var ErrMaxRetriesExceeded = errors.New("maximum number of retries exceeded")
+
+// retriesExceededError wraps the last error of a request once all retries have been exhausted.
+// It matches ErrMaxRetriesExceeded while preserving the original error.
+type retriesExceededError struct {
+	err error
+}
+
+func (e retriesExceededError) Error() string {
+	return e.err.Error()
+}
+
+func (e retriesExceededError) Unwrap() error {
+	return e.err
+}
+
+func (e retriesExceededError) Is(target error) bool {
+	return target == ErrMaxRetriesExceeded
+}
+
+// ErrNotFound is returned when a looked up object does not exist.
+var ErrNotFound = errors.New("not found")
+
+// ErrTaskTimeout is returned when a vManage task does not complete within the given timeout.
+var ErrTaskTimeout = errors.New("timeout waiting for task to complete")
+
+// ErrOnboardingTimeout is returned when a device does not complete onboarding within the given timeout.
+var ErrOnboardingTimeout = errors.New("timeout waiting for device onboarding")
+
+// ErrUnexpectedContentType is returned when a response is not JSON although JSON is expected (see ExpectJSON).
+var ErrUnexpectedContentType = errors.New("unexpected response content type")
+
+// ErrClientClosed is returned for requests sent after the client has been shut down (see Shutdown).
+var ErrClientClosed = errors.New("client closed")
+
+// ErrDeviceInVManageMode is returned when a CLI configuration is pushed to a device managed by device templates.
+var ErrDeviceInVManageMode = errors.New("device is in vManage mode and cannot accept a CLI configuration")
+
+// ErrSyncTimeout is returned when a device configuration is not in sync within the given timeout.
+var ErrSyncTimeout = errors.New("timeout waiting for device sync")
+
+// ErrClusterTimeout is returned when the vManage cluster is not healthy within the given timeout.
+var ErrClusterTimeout = errors.New("timeout waiting for cluster to be healthy")
+
+// ErrUnexpectedSchema is returned when a response does not match the expected schema (see Res.Validate).
+var ErrUnexpectedSchema = errors.New("unexpected response schema, possibly unsupported vManage version")
+
+// ErrTechSupportTimeout is returned when a tech support bundle is not collected within the given timeout.
+var ErrTechSupportTimeout = errors.New("timeout waiting for tech support collection")
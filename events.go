@@ -0,0 +1,177 @@
+package sdwan
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// EventFilter limits the events returned by StreamEvents.
+// Empty fields do not filter.
+type EventFilter struct {
+	// EventNames are the event names, e.g. "interface-state-change".
+	EventNames []string
+	// Severities are the event severities, e.g. "critical" or "major".
+	Severities []string
+	// DeviceIDs are the system IPs of the devices.
+	DeviceIDs []string
+}
+
+// query creates the query string of the filter.
+func (filter EventFilter) query() string {
+	query := url.Values{}
+	if len(filter.EventNames) > 0 {
+		query.Set("eventname", strings.Join(filter.EventNames, ","))
+	}
+	if len(filter.Severities) > 0 {
+		query.Set("severity", strings.Join(filter.Severities, ","))
+	}
+	if len(filter.DeviceIDs) > 0 {
+		query.Set("deviceId", strings.Join(filter.DeviceIDs, ","))
+	}
+	return query.Encode()
+}
+
+// Event is a vManage event notification.
+type Event struct {
+	// Name is the event name.
+	Name string
+	// Severity is the event severity.
+	Severity string
+	// DeviceID is the system IP of the device which generated the event.
+	DeviceID string
+	// Time is the time the event has been generated.
+	Time time.Time
+	// Res is the complete event.
+	Res Res
+}
+
+// newEvent creates an Event from a single event notification.
+func newEvent(res Res) Event {
+	return Event{
+		Name:     res.Get("eventname").String(),
+		Severity: res.Get("severity").String(),
+		DeviceID: res.Get("system_ip").String(),
+		Time:     time.UnixMilli(res.Get("entry_time").Int()),
+		Res:      res,
+	}
+}
+
+// StreamEvents opens a long-lived connection to the vManage event stream and sends each event to the returned channel.
+// Events are expected to be newline delimited JSON objects (or arrays of objects).
+// If the stream drops, a new connection is established following an exponential backoff algorithm.
+// Both channels are closed once the context is canceled, a non-recoverable error (e.g. invalid credentials)
+// is sent to the error channel before closing.
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	events, errs := client.StreamEvents(ctx, sdwan.EventFilter{Severities: []string{"critical"}})
+//	for event := range events {
+//		println(event.Name)
+//	}
+func (client *Client) StreamEvents(ctx context.Context, filter EventFilter) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errs := make(chan error, 1)
+
+	// the stream is long-lived, the request timeout must not apply
+	streamClient := *client.HttpClient
+	streamClient.Timeout = 0
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+		path := "/dataservice/event/stream"
+		if query := filter.query(); query != "" {
+			path += "?" + query
+		}
+		for attempts := 0; ; attempts++ {
+			err := client.Authenticate()
+			if errors.Is(err, ErrInvalidCredentials) {
+				errs <- err
+				return
+			}
+			if err == nil {
+				req := client.NewReq("GET", path, nil, Context(ctx))
+				req.HttpReq.Header.Add("X-XSRF-TOKEN", client.Token)
+				var received bool
+				received, err = client.readEventStream(ctx, &streamClient, req, events)
+				if received {
+					attempts = 0
+				}
+			}
+			if ctx.Err() != nil {
+				log.Printf("[DEBUG] Event stream closed")
+				return
+			}
+			delay := backoffDelay(attempts, client.BackoffMinDelay, client.BackoffMaxDelay, client.BackoffDelayFactor)
+			log.Printf("[ERROR] Event stream dropped: %v, reconnecting in %v, retries: %v", err, delay.Round(time.Second), attempts)
+			client.clockSleepContext(ctx, delay)
+			if ctx.Err() != nil {
+				log.Printf("[DEBUG] Event stream closed")
+				return
+			}
+		}
+	}()
+	return events, errs
+}
+
+// readEventStream reads events from a single event stream connection until it drops.
+// It returns whether at least one event has been received.
+func (client *Client) readEventStream(ctx context.Context, httpClient *http.Client, req Req, events chan<- Event) (bool, error) {
+	if err := client.signRequest(req.HttpReq); err != nil {
+		return false, err
+	}
+	log.Printf("[DEBUG] HTTP Request: %s, %s", req.HttpReq.Method, req.HttpReq.URL)
+	httpRes, err := httpClient.Do(req.HttpReq)
+	if err != nil {
+		return false, err
+	}
+	defer httpRes.Body.Close()
+	if client.authExpired(Res{}, httpRes.StatusCode) {
+		// session expired, login again before reconnecting
+		client.AuthenticationMutex.Lock()
+		client.Token = ""
+		client.AuthenticationMutex.Unlock()
+	}
+	if httpRes.StatusCode < 200 || httpRes.StatusCode > 299 {
+		return false, statusError(httpRes.StatusCode)
+	}
+
+	received := false
+	scanner := bufio.NewScanner(httpRes.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if !gjson.Valid(line) {
+			log.Printf("[ERROR] Invalid event: %s", line)
+			continue
+		}
+		res := newRes([]byte(line))
+		entries := []Res{res}
+		if res.IsArray() {
+			entries = toResArray(res.Array())
+		}
+		for _, entry := range entries {
+			select {
+			case events <- newEvent(entry):
+				received = true
+			case <-ctx.Done():
+				return received, ctx.Err()
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return received, err
+	}
+	return received, fmt.Errorf("event stream closed by server")
+}
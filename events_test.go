@@ -0,0 +1,78 @@
+package sdwan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientStreamEvents tests the Client::StreamEvents method.
+func TestClientStreamEvents(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	clock := &fakeClock{now: time.Now()}
+	withClock(clock)(&client)
+	client.BackoffMinDelay = 30
+	client.BackoffMaxDelay = 30
+
+	// Stream drops after the first event and is reconnected
+	gock.New(testURL).
+		Get("/dataservice/event/stream").
+		MatchParam("severity", "critical").
+		Reply(200).
+		BodyString(`{"eventname":"interface-state-change","severity":"critical","system_ip":"1.1.1.1"}` + "\n")
+	gock.New(testURL).
+		Get("/dataservice/event/stream").
+		Reply(200).
+		BodyString(`[{"eventname":"bfd-state-change","severity":"critical"}]` + "\n")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errs := client.StreamEvents(ctx, EventFilter{Severities: []string{"critical"}})
+	event := <-events
+	assert.Equal(t, "interface-state-change", event.Name)
+	assert.Equal(t, "1.1.1.1", event.DeviceID)
+	event = <-events
+	assert.Equal(t, "bfd-state-change", event.Name)
+	cancel()
+	clock.mutex.Lock()
+	assert.Equal(t, 30*time.Second, clock.slept[0])
+	clock.mutex.Unlock()
+
+	for range events {
+	}
+	assert.NoError(t, <-errs)
+}
+
+// TestClientStreamEventsCanceled tests canceling StreamEvents while the stream is open.
+func TestClientStreamEventsCanceled(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(200)
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+	client, _ := NewClient(server.URL, "usr", "pwd", true, BackoffMinDelay(10))
+	client.Token = "ABC"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, errs := client.StreamEvents(ctx, EventFilter{})
+	time.Sleep(50 * time.Millisecond)
+	started := time.Now()
+	cancel()
+	for range events {
+	}
+	assert.NoError(t, <-errs)
+	assert.Less(t, time.Since(started), time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,257 @@
+package sdwan
+
+import (
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// BundleSchemaVersion is the version of the document format created by ExportBundle.
+const BundleSchemaVersion = 1
+
+// readOnlyTemplateAttributes are the template attributes set by vManage which must not be sent when creating templates.
+var readOnlyTemplateAttributes = []string{
+	"templateId",
+	"@rid",
+	"createdBy",
+	"createdOn",
+	"lastUpdatedBy",
+	"lastUpdatedOn",
+	"owner",
+	"infoTag",
+	"devicesAttached",
+	"attachedMastersCount",
+	"templateAttached",
+}
+
+// ExportTemplates exports all feature and device templates into a single portable JSON document.
+// The document contains the complete template objects, including the references of device templates to feature templates.
+//
+//	{"featureTemplates": [...], "deviceTemplates": [...]}
+func (client *Client) ExportTemplates(mods ...func(*Req)) ([]byte, error) {
+	body := Body{}.SetRaw("featureTemplates", "[]").SetRaw("deviceTemplates", "[]")
+	for _, kind := range []string{"feature", "device"} {
+		list, err := client.Get("/template/"+kind, mods...)
+		if err != nil {
+			return nil, err
+		}
+		for _, template := range list.Get("data").Array() {
+			res, err := client.Get("/template/"+kind+"/object/"+template.Get("templateId").String(), mods...)
+			if err != nil {
+				return nil, err
+			}
+			// the object endpoint does not return all attributes of the list endpoint
+			object := Body{Str: res.Raw}.
+				Set("templateId", template.Get("templateId").String()).
+				SetRaw("factoryDefault", strconv.FormatBool(template.Get("factoryDefault").Bool()))
+			body = body.SetRaw(kind+"Templates.-1", object.Str)
+		}
+	}
+	return []byte(body.Str), nil
+}
+
+// ImportTemplates recreates the feature and device templates of a document created by ExportTemplates.
+// Feature templates are created first, the references of device templates are updated to the IDs of the new feature templates.
+// Factory default templates are not created but mapped to the existing templates with the same name.
+// Documents created by ExportBundle are checked against the vManage version first, compatibility issues are logged as warnings.
+func (client *Client) ImportTemplates(data []byte, mods ...func(*Req)) error {
+	doc := Body{Str: string(data)}
+	if err := doc.Validate(); err != nil {
+		return err
+	}
+	if doc.Res().Get("metadata").Exists() {
+		client.checkBundleCompatibility(data, mods...)
+	}
+	existing, err := client.Get("/template/feature", mods...)
+	if err != nil {
+		return err
+	}
+	existingIDs := map[string]string{}
+	for _, template := range existing.Get("data").Array() {
+		existingIDs[template.Get("templateName").String()] = template.Get("templateId").String()
+	}
+
+	ids := map[string]string{}
+	for _, template := range doc.Res().Get("featureTemplates").Array() {
+		oldID := template.Get("templateId").String()
+		name := template.Get("templateName").String()
+		if template.Get("factoryDefault").Bool() {
+			newID, ok := existingIDs[name]
+			if !ok {
+				return fmt.Errorf("factory default feature template %s: %w", name, ErrNotFound)
+			}
+			ids[oldID] = newID
+			continue
+		}
+		res, err := client.Post("/template/feature", stripReadOnlyAttributes(template.Raw), mods...)
+		if err != nil {
+			return fmt.Errorf("failed to import feature template %s: %w", name, err)
+		}
+		ids[oldID] = res.Get("templateId").String()
+		log.Printf("[DEBUG] Imported feature template %s", name)
+	}
+
+	for _, template := range doc.Res().Get("deviceTemplates").Array() {
+		name := template.Get("templateName").String()
+		if template.Get("factoryDefault").Bool() {
+			continue
+		}
+		raw := stripReadOnlyAttributes(template.Raw)
+		for oldID, newID := range ids {
+			raw = strings.ReplaceAll(raw, `"`+oldID+`"`, `"`+newID+`"`)
+		}
+		path := "/template/device/feature"
+		if template.Get("configType").String() == "file" {
+			path = "/template/device/cli"
+		}
+		if _, err := client.Post(path, raw, mods...); err != nil {
+			return fmt.Errorf("failed to import device template %s: %w", name, err)
+		}
+		log.Printf("[DEBUG] Imported device template %s", name)
+	}
+	return nil
+}
+
+// Bundle is an export of the templates of a vManage instance, including the metadata needed to import it
+// into another vManage release.
+type Bundle struct {
+	// SchemaVersion is the version of the document format, 0 if the document has no metadata.
+	SchemaVersion int
+	// VManageVersion is the version of the vManage the bundle has been exported from, e.g. "20.9.1".
+	VManageVersion string
+	// ExportedAt is the time of the export.
+	ExportedAt time.Time
+	// Data is the complete document, which can be imported using ImportTemplates.
+	Data []byte
+}
+
+// Warning describes a compatibility issue of a Bundle.
+type Warning struct {
+	Message string
+}
+
+func (w Warning) String() string {
+	return w.Message
+}
+
+// ExportBundle exports all feature and device templates (see ExportTemplates) together with the vManage version
+// and the schema version of the document:
+//
+//	{"metadata": {"schemaVersion": 1, "vmanageVersion": "20.9.1", "exportedAt": "..."}, "featureTemplates": [...], "deviceTemplates": [...]}
+func (client *Client) ExportBundle(mods ...func(*Req)) (Bundle, error) {
+	version, err := client.ServerVersion(mods...)
+	if err != nil {
+		return Bundle{}, err
+	}
+	data, err := client.ExportTemplates(mods...)
+	if err != nil {
+		return Bundle{}, err
+	}
+	bundle := Bundle{
+		SchemaVersion:  BundleSchemaVersion,
+		VManageVersion: version,
+		ExportedAt:     client.clockNow().UTC().Truncate(time.Second),
+	}
+	metadata := Body{}.
+		SetRaw("schemaVersion", strconv.Itoa(bundle.SchemaVersion)).
+		Set("vmanageVersion", bundle.VManageVersion).
+		Set("exportedAt", bundle.ExportedAt.Format(time.RFC3339))
+	bundle.Data = []byte(Body{Str: string(data)}.SetRaw("metadata", metadata.Str).Str)
+	return bundle, nil
+}
+
+// ParseBundle parses a document created by ExportBundle or ExportTemplates.
+func ParseBundle(data []byte) (Bundle, error) {
+	doc := Body{Str: string(data)}
+	if err := doc.Validate(); err != nil {
+		return Bundle{}, err
+	}
+	metadata := doc.Res().Get("metadata")
+	bundle := Bundle{
+		SchemaVersion:  int(metadata.Get("schemaVersion").Int()),
+		VManageVersion: metadata.Get("vmanageVersion").String(),
+		Data:           data,
+	}
+	if exportedAt := metadata.Get("exportedAt").String(); exportedAt != "" {
+		t, err := time.Parse(time.RFC3339, exportedAt)
+		if err != nil {
+			return Bundle{}, fmt.Errorf("invalid bundle export time: %w", err)
+		}
+		bundle.ExportedAt = t
+	}
+	return bundle, nil
+}
+
+// CompatibleWith checks whether the bundle can be safely imported into a vManage of the given version, e.g. "20.12.1".
+// Releases are compared by their major and minor version. An empty result means no issues have been found.
+func (bundle Bundle) CompatibleWith(targetVersion string) []Warning {
+	warnings := []Warning{}
+	if bundle.SchemaVersion == 0 {
+		return append(warnings, Warning{"bundle has no version metadata, compatibility can not be checked"})
+	}
+	if bundle.SchemaVersion > BundleSchemaVersion {
+		warnings = append(warnings, Warning{fmt.Sprintf("bundle schema version %d is newer than the supported schema version %d", bundle.SchemaVersion, BundleSchemaVersion)})
+	}
+	source, ok := parseRelease(bundle.VManageVersion)
+	if !ok {
+		return append(warnings, Warning{fmt.Sprintf("unknown source vManage version %q, compatibility can not be checked", bundle.VManageVersion)})
+	}
+	target, ok := parseRelease(targetVersion)
+	if !ok {
+		return append(warnings, Warning{fmt.Sprintf("unknown target vManage version %q, compatibility can not be checked", targetVersion)})
+	}
+	switch {
+	case source[0] > target[0] || source[0] == target[0] && source[1] > target[1]:
+		warnings = append(warnings, Warning{fmt.Sprintf("bundle exported from vManage %s is imported into the older release %s, templates might use unsupported parameters", bundle.VManageVersion, targetVersion)})
+	case source != target:
+		warnings = append(warnings, Warning{fmt.Sprintf("bundle exported from vManage %s is imported into the newer release %s, template parameters might have changed", bundle.VManageVersion, targetVersion)})
+	}
+	return warnings
+}
+
+// parseRelease returns the major and minor version of a vManage version, e.g. [20 9] for "20.9.1".
+func parseRelease(version string) ([2]int, bool) {
+	parts := strings.SplitN(version, ".", 3)
+	if len(parts) < 2 {
+		return [2]int{}, false
+	}
+	release := [2]int{}
+	for i := range release {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return [2]int{}, false
+		}
+		release[i] = n
+	}
+	return release, true
+}
+
+// checkBundleCompatibility logs the compatibility warnings of a bundle with the vManage of the client.
+func (client *Client) checkBundleCompatibility(data []byte, mods ...func(*Req)) {
+	bundle, err := ParseBundle(data)
+	if err != nil {
+		log.Printf("[WARNING] Bundle compatibility check failed: %v", err)
+		return
+	}
+	version, err := client.ServerVersion(mods...)
+	if err != nil {
+		log.Printf("[WARNING] Bundle compatibility check failed: %v", err)
+		return
+	}
+	for _, warning := range bundle.CompatibleWith(version) {
+		log.Printf("[WARNING] %s", warning)
+	}
+}
+
+// stripReadOnlyAttributes removes the attributes set by vManage from a template.
+func stripReadOnlyAttributes(template string) string {
+	body := Body{Str: template}
+	for _, attribute := range readOnlyTemplateAttributes {
+		body = body.Delete(gjson.Escape(attribute))
+	}
+	return body.Delete("factoryDefault").Str
+}
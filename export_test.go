@@ -0,0 +1,75 @@
+package sdwan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientExportImportTemplates tests the Client::ExportTemplates and Client::ImportTemplates methods.
+func TestClientExportImportTemplates(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Export
+	gock.New(testURL).Get("/dataservice/template/feature").Reply(200).BodyString(`{"data":[{"templateId":"F1","templateName":"system","factoryDefault":false},{"templateId":"F2","templateName":"Factory_Default_AAA","factoryDefault":true}]}`)
+	gock.New(testURL).Get("/dataservice/template/feature/object/F1").Reply(200).BodyString(`{"templateName":"system","@rid":5,"createdOn":1}`)
+	gock.New(testURL).Get("/dataservice/template/feature/object/F2").Reply(200).BodyString(`{"templateName":"Factory_Default_AAA"}`)
+	gock.New(testURL).Get("/dataservice/template/device").Reply(200).BodyString(`{"data":[{"templateId":"D1","templateName":"edge"}]}`)
+	gock.New(testURL).Get("/dataservice/template/device/object/D1").Reply(200).BodyString(`{"templateName":"edge","configType":"template","generalTemplates":[{"templateId":"F1"},{"templateId":"F2"}]}`)
+	data, err := client.ExportTemplates()
+	assert.NoError(t, err)
+	doc := Body{Str: string(data)}.Res()
+	assert.Len(t, doc.Get("featureTemplates").Array(), 2)
+	assert.Len(t, doc.Get("deviceTemplates").Array(), 1)
+
+	// Import
+	gock.New(testURL).Get("/dataservice/template/feature").Reply(200).BodyString(`{"data":[{"templateId":"F20","templateName":"Factory_Default_AAA"}]}`)
+	gock.New(testURL).Post("/dataservice/template/feature").BodyString(`{"templateName":"system"}`).Reply(200).BodyString(`{"templateId":"F10"}`)
+	gock.New(testURL).
+		Post("/dataservice/template/device/feature").
+		BodyString(`{"templateName":"edge","configType":"template","generalTemplates":[{"templateId":"F10"},{"templateId":"F20"}]}`).
+		Reply(200)
+	assert.NoError(t, client.ImportTemplates(data))
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientExportBundle tests the Client::ExportBundle method.
+func TestClientExportBundle(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	withClock(&fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 500, time.UTC)})(&client)
+
+	gock.New(testURL).Get("/dataservice/client/server").Reply(200).BodyString(`{"data":{"platformVersion":"20.9.1"}}`)
+	gock.New(testURL).Get("/dataservice/template/feature").Reply(200).BodyString(`{"data":[]}`)
+	gock.New(testURL).Get("/dataservice/template/device").Reply(200).BodyString(`{"data":[]}`)
+	bundle, err := client.ExportBundle()
+	assert.NoError(t, err)
+	assert.Equal(t, BundleSchemaVersion, bundle.SchemaVersion)
+	assert.Equal(t, "20.9.1", bundle.VManageVersion)
+	assert.Equal(t, time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), bundle.ExportedAt)
+
+	parsed, err := ParseBundle(bundle.Data)
+	assert.NoError(t, err)
+	assert.Equal(t, bundle, parsed)
+
+	// Import checks the compatibility
+	gock.New(testURL).Get("/dataservice/client/server").Reply(200).BodyString(`{"data":{"platformVersion":"20.12.1"}}`)
+	gock.New(testURL).Get("/dataservice/template/feature").Reply(200).BodyString(`{"data":[]}`)
+	assert.NoError(t, client.ImportTemplates(bundle.Data))
+	assert.True(t, gock.IsDone())
+}
+
+// TestBundleCompatibleWith tests the Bundle::CompatibleWith method.
+func TestBundleCompatibleWith(t *testing.T) {
+	bundle := Bundle{SchemaVersion: BundleSchemaVersion, VManageVersion: "20.9.1"}
+	assert.Empty(t, bundle.CompatibleWith("20.9.3"))
+	assert.Len(t, bundle.CompatibleWith("20.12.1"), 1)
+	assert.Contains(t, bundle.CompatibleWith("20.6.1")[0].Message, "older release")
+	assert.Contains(t, bundle.CompatibleWith("latest")[0].Message, "unknown target")
+
+	assert.Contains(t, Bundle{}.CompatibleWith("20.9.1")[0].Message, "no version metadata")
+	assert.Len(t, Bundle{SchemaVersion: BundleSchemaVersion + 1, VManageVersion: "20.9.1"}.CompatibleWith("20.9.1"), 1)
+}
@@ -6,6 +6,7 @@ require (
 	github.com/stretchr/testify v1.9.0
 	github.com/tidwall/gjson v1.17.1
 	github.com/tidwall/sjson v1.2.5
+	golang.org/x/sync v0.7.0
 	gopkg.in/h2non/gock.v1 v1.1.2
 )
 
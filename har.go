@@ -0,0 +1,185 @@
+package sdwan
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// harRedacted replaces sensitive headers and payloads in recorded HAR entries.
+const harRedacted = "REDACTED"
+
+// harSensitiveHeaders are headers which are never written to a HAR file.
+var harSensitiveHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+	"X-Xsrf-Token":  true,
+	// tenant session IDs, see OnBehalfOfTenant
+	http.CanonicalHeaderKey(TenantSessionHeader): true,
+}
+
+// harRecorder collects request/response pairs and writes them to an HTTP Archive (HAR 1.2) file.
+type harRecorder struct {
+	path    string
+	mutex   sync.Mutex
+	entries []harEntry
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harTimings struct {
+	Send    int `json:"send"`
+	Wait    int `json:"wait"`
+	Receive int `json:"receive"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            int         `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+// harHeaders converts HTTP headers to HAR headers, sensitive values are redacted.
+func harHeaders(header http.Header) []harNameValue {
+	headers := []harNameValue{}
+	for name, values := range header {
+		for _, value := range values {
+			if harSensitiveHeaders[http.CanonicalHeaderKey(name)] {
+				value = harRedacted
+			}
+			headers = append(headers, harNameValue{Name: name, Value: value})
+		}
+	}
+	return headers
+}
+
+// record adds a request/response pair. Payloads of requests with LogPayload disabled are redacted.
+func (recorder *harRecorder) record(req Req, reqBody []byte, httpRes *http.Response, resBody []byte, started time.Time, duration time.Duration) {
+	elapsed := int(duration.Milliseconds())
+	reqText, resText := string(reqBody), string(resBody)
+	if !req.LogPayload {
+		reqText, resText = harRedacted, harRedacted
+	}
+	query := []harNameValue{}
+	for name, values := range req.HttpReq.URL.Query() {
+		for _, value := range values {
+			query = append(query, harNameValue{Name: name, Value: value})
+		}
+	}
+	entry := harEntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            elapsed,
+		Request: harRequest{
+			Method:      req.HttpReq.Method,
+			URL:         req.HttpReq.URL.String(),
+			HTTPVersion: req.HttpReq.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(req.HttpReq.Header),
+			QueryString: query,
+			HeadersSize: -1,
+			BodySize:    len(reqBody),
+		},
+		Response: harResponse{
+			Status:      httpRes.StatusCode,
+			StatusText:  http.StatusText(httpRes.StatusCode),
+			HTTPVersion: httpRes.Proto,
+			Cookies:     []harNameValue{},
+			Headers:     harHeaders(httpRes.Header),
+			Content: harContent{
+				Size:     len(resBody),
+				MimeType: httpRes.Header.Get("Content-Type"),
+				Text:     resText,
+			},
+			HeadersSize: -1,
+			BodySize:    len(resBody),
+		},
+		Timings: harTimings{Send: 0, Wait: elapsed, Receive: 0},
+	}
+	if len(reqBody) > 0 {
+		entry.Request.PostData = &harPostData{MimeType: req.HttpReq.Header.Get("Content-Type"), Text: reqText}
+	}
+	recorder.mutex.Lock()
+	recorder.entries = append(recorder.entries, entry)
+	recorder.mutex.Unlock()
+}
+
+// flush writes all recorded entries to the HAR file.
+func (recorder *harRecorder) flush() error {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	har := map[string]interface{}{
+		"log": map[string]interface{}{
+			"version": "1.2",
+			"creator": map[string]string{"name": "go-sdwan", "version": ""},
+			"entries": append([]harEntry{}, recorder.entries...),
+		},
+	}
+	data, err := json.MarshalIndent(har, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(recorder.path, data, 0600)
+}
+
+// recordExchange records a request/response pair if RecordHAR or RequestRecorder is enabled.
+func (client *Client) recordExchange(req Req, reqBody []byte, httpRes *http.Response, resBody []byte, started time.Time) {
+	duration := client.clockNow().Sub(started)
+	if client.harRecorder != nil {
+		client.harRecorder.record(req, reqBody, httpRes, resBody, started, duration)
+	}
+	if client.recentRequests != nil {
+		client.recentRequests.add(newRecentRequest(req, reqBody, httpRes, resBody, nil, started, duration))
+	}
+}
+
+// Close releases resources of the client, e.g. it writes the HAR file if RecordHAR is enabled.
+func (client *Client) Close() error {
+	if client.harRecorder != nil {
+		return client.harRecorder.flush()
+	}
+	return nil
+}
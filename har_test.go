@@ -0,0 +1,51 @@
+package sdwan
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tidwall/gjson"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientRecordHAR tests the RecordHAR modifier.
+func TestClientRecordHAR(t *testing.T) {
+	defer gock.Off()
+	path := filepath.Join(t.TempDir(), "session.har")
+	client, _ := NewClient(testURL, "usr", "pwd", true, MaxRetries(0), RecordHAR(path))
+	gock.InterceptClient(client.HttpClient)
+	client.Token = "ABC"
+
+	gock.New(testURL).Post("/dataservice/url").Reply(200).SetHeader("Set-Cookie", "JSESSIONID=123").BodyString(`{"a":1}`)
+	gock.New(testURL).Post("/dataservice/password").Reply(200).BodyString(`{"password":"secret"}`)
+	_, err := client.Post("/url?x=1", `{"b":2}`)
+	assert.NoError(t, err)
+	_, err = client.Post("/password", `{"password":"secret"}`, NoLogPayload)
+	assert.NoError(t, err)
+	gock.New(testURL).Post("/dataservice/tenant/T1/vsessionid").Reply(200).BodyString(`{"VSessionId":"TENANTSESSION"}`)
+	gock.New(testURL).Get("/dataservice/tenant-url").MatchHeader(TenantSessionHeader, "TENANTSESSION").Reply(200)
+	_, err = client.Get("/tenant-url", OnBehalfOfTenant("T1"))
+	assert.NoError(t, err)
+	assert.NoError(t, client.Close())
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	har := gjson.ParseBytes(data)
+	assert.Equal(t, "1.2", har.Get("log.version").String())
+	assert.Equal(t, int64(4), har.Get("log.entries.#").Int())
+	entry := har.Get("log.entries.0")
+	assert.Equal(t, "POST", entry.Get("request.method").String())
+	assert.Equal(t, testURL+"/dataservice/url?x=1", entry.Get("request.url").String())
+	assert.Equal(t, "1", entry.Get(`request.queryString.#(name=="x").value`).String())
+	assert.Equal(t, `{"b":2}`, entry.Get("request.postData.text").String())
+	assert.Equal(t, "REDACTED", entry.Get(`request.headers.#(name=="X-Xsrf-Token").value`).String())
+	assert.Equal(t, "REDACTED", entry.Get(`response.headers.#(name=="Set-Cookie").value`).String())
+	assert.Equal(t, int64(200), entry.Get("response.status").Int())
+	assert.Equal(t, `{"a":1}`, entry.Get("response.content.text").String())
+	assert.NotContains(t, string(data), "secret")
+	assert.NotContains(t, string(data), "ABC")
+	assert.Equal(t, "REDACTED", har.Get(`log.entries.3.request.headers.#(name=="Vsessionid").value`).String())
+	assert.NotContains(t, string(data), "TENANTSESSION")
+}
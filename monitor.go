@@ -0,0 +1,75 @@
+package sdwan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// PollDeviceMetric periodically retrieves a real-time monitoring metric of a device identified by its system IP and
+// sends each response to the returned channel. The metric is the monitoring endpoint below /device, e.g. "interface",
+// "bfd/sessions" or "system/status". The first request is sent immediately, subsequent ones after each interval.
+// Failed requests do not stop polling, the next attempt follows an exponential backoff algorithm (but never happens
+// before the interval has passed). Both channels are closed once the context is canceled, a non-recoverable error
+// (e.g. invalid credentials or an unknown metric) is sent to the error channel before closing.
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	results, errs := client.PollDeviceMetric(ctx, "10.0.0.1", "interface", 30*time.Second)
+//	for res := range results {
+//		println(res.Get("data.#").Int())
+//	}
+func (client *Client) PollDeviceMetric(ctx context.Context, deviceID, metric string, interval time.Duration) (<-chan Res, <-chan error) {
+	results := make(chan Res)
+	errs := make(chan error, 1)
+
+	metric = strings.Trim(metric, "/")
+	if metric == "" || strings.ContainsAny(metric, "?#") || deviceID == "" || interval <= 0 {
+		errs <- fmt.Errorf("invalid metric poll: device %q, metric %q, interval %v", deviceID, metric, interval)
+		close(results)
+		close(errs)
+		return results, errs
+	}
+	path := "/device/" + metric + "?deviceId=" + url.QueryEscape(deviceID)
+
+	go func() {
+		defer close(results)
+		defer close(errs)
+		for attempts := 0; ; {
+			delay := interval
+			res, err := client.Get(path, Context(ctx))
+			if ctx.Err() != nil {
+				log.Printf("[DEBUG] Metric poll closed: %s", path)
+				return
+			}
+			if errors.Is(err, ErrInvalidCredentials) || errors.Is(err, ErrNotFound) {
+				errs <- err
+				return
+			}
+			if err == nil {
+				attempts = 0
+				select {
+				case <-ctx.Done():
+					log.Printf("[DEBUG] Metric poll closed: %s", path)
+					return
+				case results <- res:
+				}
+			} else {
+				if backoff := backoffDelay(attempts, client.BackoffMinDelay, client.BackoffMaxDelay, client.BackoffDelayFactor); backoff > delay {
+					delay = backoff
+				}
+				log.Printf("[ERROR] Metric poll failed: %v, retrying in %v, retries: %v", err, delay.Round(time.Second), attempts)
+				attempts++
+			}
+			client.clockSleepContext(ctx, delay)
+			if ctx.Err() != nil {
+				log.Printf("[DEBUG] Metric poll closed: %s", path)
+				return
+			}
+		}
+	}()
+	return results, errs
+}
@@ -0,0 +1,97 @@
+package sdwan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientPollDeviceMetric tests the Client::PollDeviceMetric method.
+func TestClientPollDeviceMetric(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.MaxRetries = 0
+	client.BackoffMinDelay = 0
+
+	// A failed poll does not stop polling
+	gock.New(testURL).Get("/dataservice/device/interface").MatchParam("deviceId", "10.0.0.1").Reply(200).
+		BodyString(`{"data":[{"ifname":"ge0/0"}]}`)
+	gock.New(testURL).Get("/dataservice/device/interface").MatchParam("deviceId", "10.0.0.1").Reply(500)
+	gock.New(testURL).Get("/dataservice/device/interface").MatchParam("deviceId", "10.0.0.1").Reply(200).
+		BodyString(`{"data":[{"ifname":"ge0/1"}]}`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, errs := client.PollDeviceMetric(ctx, "10.0.0.1", "interface", time.Millisecond)
+	res := <-results
+	assert.Equal(t, "ge0/0", res.Get("data.0.ifname").String())
+	res = <-results
+	assert.Equal(t, "ge0/1", res.Get("data.0.ifname").String())
+	cancel()
+
+	for range results {
+	}
+	assert.NoError(t, <-errs)
+
+	// Unknown metric
+	gock.New(testURL).Get("/dataservice/device/unknown").Reply(404)
+	results, errs = client.PollDeviceMetric(context.Background(), "10.0.0.1", "unknown", time.Millisecond)
+	for range results {
+	}
+	assert.ErrorIs(t, <-errs, ErrNotFound)
+
+	// Invalid arguments
+	_, errs = client.PollDeviceMetric(context.Background(), "10.0.0.1", "interface", 0)
+	assert.Error(t, <-errs)
+
+	// Interval and backoff on the client clock
+	clock := &fakeClock{now: time.Now()}
+	withClock(clock)(&client)
+	client.BackoffMinDelay = 120
+	client.BackoffMaxDelay = 120
+	gock.New(testURL).Get("/dataservice/device/interface").Reply(200).BodyString(`{"data":[]}`)
+	gock.New(testURL).Get("/dataservice/device/interface").Reply(500)
+	gock.New(testURL).Get("/dataservice/device/interface").Reply(200).BodyString(`{"data":[]}`)
+	ctx, cancel = context.WithCancel(context.Background())
+	results, errs = client.PollDeviceMetric(ctx, "10.0.0.1", "interface", time.Minute)
+	<-results
+	<-results
+	cancel()
+	for range results {
+	}
+	assert.NoError(t, <-errs)
+	clock.mutex.Lock()
+	assert.Equal(t, []time.Duration{time.Minute, 2 * time.Minute}, clock.slept[:2])
+	clock.mutex.Unlock()
+}
+
+// TestClientPollDeviceMetricCanceled tests canceling PollDeviceMetric during a request.
+func TestClientPollDeviceMetricCanceled(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+	client, _ := NewClient(server.URL, "usr", "pwd", true, BackoffMinDelay(10))
+	client.Token = "ABC"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results, errs := client.PollDeviceMetric(ctx, "10.0.0.1", "interface", time.Minute)
+	time.Sleep(50 * time.Millisecond)
+	started := time.Now()
+	cancel()
+	for range results {
+	}
+	assert.NoError(t, <-errs)
+	assert.Less(t, time.Since(started), time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
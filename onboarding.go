@@ -0,0 +1,98 @@
+package sdwan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// onboardingPollInterval is the delay between two onboarding status checks.
+const onboardingPollInterval = 10 * time.Second
+
+// Onboarding stages of a WAN edge device, in the order they are passed.
+const (
+	OnboardingStageCertificate        = "certificate"
+	OnboardingStageControlConnections = "control-connections"
+	OnboardingStageSync               = "sync"
+	OnboardingStageDone               = "done"
+)
+
+// OnboardingStatus is the consolidated onboarding status of a WAN edge device.
+type OnboardingStatus struct {
+	// DeviceID is the UUID (chassis number) of the device.
+	DeviceID string
+	// CertificateInstalled indicates whether the device certificate has been installed.
+	CertificateInstalled bool
+	// ControlConnectionsUp indicates whether the device is reachable through its control connections.
+	ControlConnectionsUp bool
+	// InSync indicates whether the device configuration is in sync with vManage.
+	InSync bool
+	// Stage is the first stage which has not been passed yet, or OnboardingStageDone.
+	Stage string
+}
+
+// GetOnboardingStatus retrieves the current onboarding status of a WAN edge device.
+func (client *Client) GetOnboardingStatus(deviceID string, mods ...func(*Req)) (OnboardingStatus, error) {
+	status := OnboardingStatus{DeviceID: deviceID}
+	query := "?uuid=" + url.QueryEscape(deviceID)
+	edges, err := client.Get("/system/device/vedges"+query, mods...)
+	if err != nil {
+		return status, err
+	}
+	edge := edges.Get("data.0")
+	if !edge.Exists() {
+		return status, fmt.Errorf("device %s: %w", deviceID, ErrNotFound)
+	}
+	status.CertificateInstalled = edge.Get("vedgeCertificateState").String() == "certinstalled"
+	status.InSync = strings.EqualFold(edge.Get("configStatusMessage").String(), "In Sync")
+	if status.CertificateInstalled {
+		devices, err := client.Get("/device"+query, mods...)
+		if err != nil {
+			return status, err
+		}
+		status.ControlConnectionsUp = devices.Get("data.0.reachability").String() == "reachable"
+	}
+
+	switch {
+	case !status.CertificateInstalled:
+		status.Stage = OnboardingStageCertificate
+	case !status.ControlConnectionsUp:
+		status.Stage = OnboardingStageControlConnections
+	case !status.InSync:
+		status.Stage = OnboardingStageSync
+	default:
+		status.Stage = OnboardingStageDone
+	}
+	return status, nil
+}
+
+// WaitForOnboarding polls the onboarding status of a WAN edge device until the certificate is installed,
+// the control connections are up and the configuration is in sync. If the timeout expires, an error
+// wrapping ErrOnboardingTimeout and naming the stuck stage is returned along with the last status.
+//
+//	status, err := client.WaitForOnboarding("C8K-12345678-ABCD-EFGH-IJKL-123456789012", 30*time.Minute)
+func (client *Client) WaitForOnboarding(deviceID string, timeout time.Duration, mods ...func(*Req)) (OnboardingStatus, error) {
+	var status OnboardingStatus
+	err := client.poll(context.Background(), client.clockNow().Add(timeout), onboardingPollInterval, func() (bool, error) {
+		var err error
+		status, err = client.GetOnboardingStatus(deviceID, mods...)
+		if err != nil {
+			return false, err
+		}
+		if status.Stage == OnboardingStageDone {
+			log.Printf("[DEBUG] Device %s onboarded", deviceID)
+			return true, nil
+		}
+		log.Printf("[DEBUG] Waiting for onboarding of device %s, stage: %s", deviceID, status.Stage)
+		return false, nil
+	})
+	if errors.Is(err, errPollDeadline) {
+		log.Printf("[ERROR] Device %s not onboarded after %v, stage: %s", deviceID, timeout, status.Stage)
+		return status, fmt.Errorf("%w: device %s, stage: %s", ErrOnboardingTimeout, deviceID, status.Stage)
+	}
+	return status, err
+}
@@ -0,0 +1,43 @@
+package sdwan
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientWaitForOnboarding tests the Client::WaitForOnboarding method.
+func TestClientWaitForOnboarding(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	withClock(&fakeClock{now: time.Now()})(&client)
+
+	// Onboarded after certificate installation
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV1").Reply(200).
+		BodyString(`{"data":[{"vedgeCertificateState":"tokengenerated"}]}`)
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV1").Reply(200).
+		BodyString(`{"data":[{"vedgeCertificateState":"certinstalled","configStatusMessage":"In Sync"}]}`)
+	gock.New(testURL).Get("/dataservice/device").MatchParam("uuid", "DEV1").Reply(200).
+		BodyString(`{"data":[{"reachability":"reachable"}]}`)
+	status, err := client.WaitForOnboarding("DEV1", time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, OnboardingStatus{DeviceID: "DEV1", CertificateInstalled: true, ControlConnectionsUp: true, InSync: true, Stage: OnboardingStageDone}, status)
+
+	// Stuck waiting for control connections
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV1").Reply(200).
+		BodyString(`{"data":[{"vedgeCertificateState":"certinstalled","configStatusMessage":"In Sync"}]}`)
+	gock.New(testURL).Get("/dataservice/device").MatchParam("uuid", "DEV1").Reply(200).
+		BodyString(`{"data":[{"reachability":"unreachable"}]}`)
+	status, err = client.WaitForOnboarding("DEV1", 0)
+	assert.True(t, errors.Is(err, ErrOnboardingTimeout))
+	assert.Equal(t, OnboardingStageControlConnections, status.Stage)
+
+	// Unknown device
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV2").Reply(200).BodyString(`{"data":[]}`)
+	_, err = client.WaitForOnboarding("DEV2", time.Minute)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, gock.IsDone())
+}
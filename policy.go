@@ -0,0 +1,108 @@
+package sdwan
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// policyPaths maps policy types to their endpoints.
+var policyPaths = map[string]string{
+	"centralized": "/template/policy/vsmart",
+	"vsmart":      "/template/policy/vsmart",
+	"localized":   "/template/policy/vedge",
+	"vedge":       "/template/policy/vedge",
+	"security":    "/template/policy/security",
+	"voice":       "/template/policy/voice",
+}
+
+// policyDefinitionPaths maps policy definition types to their endpoints.
+var policyDefinitionPaths = map[string]string{
+	"hub-and-spoke":        "/template/policy/definition/hubandspoke",
+	"mesh":                 "/template/policy/definition/mesh",
+	"control":              "/template/policy/definition/control",
+	"vpn-membership":       "/template/policy/definition/vpnmembershipgroup",
+	"data":                 "/template/policy/definition/data",
+	"app-route":            "/template/policy/definition/approute",
+	"cflowd":               "/template/policy/definition/cflowd",
+	"qos-map":              "/template/policy/definition/qosmap",
+	"rewrite-rule":         "/template/policy/definition/rewriterule",
+	"acl":                  "/template/policy/definition/acl",
+	"acl-ipv6":             "/template/policy/definition/aclv6",
+	"route-policy":         "/template/policy/definition/vedgeroute",
+	"device-access":        "/template/policy/definition/deviceaccesspolicy",
+	"zone-based-firewall":  "/template/policy/definition/zonebasedfw",
+	"intrusion-prevention": "/template/policy/definition/intrusionprevention",
+	"url-filtering":        "/template/policy/definition/urlfiltering",
+	"amp":                  "/template/policy/definition/advancedMalwareProtection",
+	"dns-security":         "/template/policy/definition/dnssecurity",
+}
+
+// ListPolicies retrieves all policies of a given type:
+//   - "centralized" (or "vsmart")
+//   - "localized" (or "vedge")
+//   - "security"
+//   - "voice"
+func (client *Client) ListPolicies(policyType string, mods ...func(*Req)) ([]Res, error) {
+	path, ok := policyPaths[strings.ToLower(policyType)]
+	if !ok {
+		return []Res{}, fmt.Errorf("unknown policy type: %s", policyType)
+	}
+	return client.GetData(path, mods...)
+}
+
+// GetPolicyDefinition retrieves a policy definition of a given type by its ID.
+// Supported types are "hub-and-spoke", "mesh", "control", "vpn-membership", "data", "app-route", "cflowd",
+// "qos-map", "rewrite-rule", "acl", "acl-ipv6", "route-policy", "device-access", "zone-based-firewall",
+// "intrusion-prevention", "url-filtering", "amp" and "dns-security".
+func (client *Client) GetPolicyDefinition(defType, id string, mods ...func(*Req)) (Res, error) {
+	path, ok := policyDefinitionPaths[strings.ToLower(defType)]
+	if !ok {
+		return Res{}, fmt.Errorf("unknown policy definition type: %s", defType)
+	}
+	return client.Get(path+"/"+id, mods...)
+}
+
+// policyListPath returns the endpoint of a policy list type, e.g. "prefix", "site", "vpn" or "dataprefix".
+func policyListPath(listType string) (string, error) {
+	if listType == "" || strings.ContainsAny(listType, "/?#") {
+		return "", fmt.Errorf("invalid policy list type: %s", listType)
+	}
+	return "/template/policy/list/" + strings.ToLower(listType), nil
+}
+
+// ListPolicyLists retrieves all policy lists of a given type, e.g. "prefix", "site", "vpn", "dataprefix",
+// "tloc", "color", "community" or "app". All list types share the same endpoint shape.
+func (client *Client) ListPolicyLists(listType string, mods ...func(*Req)) ([]Res, error) {
+	path, err := policyListPath(listType)
+	if err != nil {
+		return []Res{}, err
+	}
+	return client.GetData(path, mods...)
+}
+
+// CreatePolicyList creates a policy list of a given type and returns its ID.
+//
+//	body := Body{}.Set("name", "SITES").Set("type", "site").SetRaw("entries", `[{"siteId":"100"}]`)
+//	id, err := client.CreatePolicyList("site", body)
+func (client *Client) CreatePolicyList(listType string, body Body, mods ...func(*Req)) (string, error) {
+	path, err := policyListPath(listType)
+	if err != nil {
+		return "", err
+	}
+	if err := body.Validate(); err != nil {
+		return "", err
+	}
+	id, _, err := client.CreateAndGetID(path, body.Str, mods...)
+	return id, err
+}
+
+// DeletePolicyList deletes a policy list of a given type by its ID.
+func (client *Client) DeletePolicyList(listType, id string, mods ...func(*Req)) error {
+	path, err := policyListPath(listType)
+	if err != nil {
+		return err
+	}
+	_, err = client.Delete(path+"/"+url.PathEscape(id), mods...)
+	return err
+}
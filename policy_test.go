@@ -0,0 +1,61 @@
+package sdwan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientListPolicies tests the Client::ListPolicies method.
+func TestClientListPolicies(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/template/policy/vsmart").Reply(200).BodyString(`{"data":[{"policyId":"P1"}]}`)
+	policies, err := client.ListPolicies("Centralized")
+	assert.NoError(t, err)
+	assert.Equal(t, "P1", policies[0].Get("policyId").String())
+
+	_, err = client.ListPolicies("unknown")
+	assert.Error(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientGetPolicyDefinition tests the Client::GetPolicyDefinition method.
+func TestClientGetPolicyDefinition(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/template/policy/definition/approute/D1").Reply(200).BodyString(`{"definitionId":"D1"}`)
+	res, err := client.GetPolicyDefinition("app-route", "D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "D1", res.Get("definitionId").String())
+
+	_, err = client.GetPolicyDefinition("unknown", "D1")
+	assert.Error(t, err)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientPolicyLists tests the Client::ListPolicyLists, Client::CreatePolicyList and Client::DeletePolicyList methods.
+func TestClientPolicyLists(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/template/policy/list/prefix").Reply(200).BodyString(`{"data":[{"listId":"L1"}]}`)
+	lists, err := client.ListPolicyLists("Prefix")
+	assert.NoError(t, err)
+	assert.Equal(t, "L1", lists[0].Get("listId").String())
+
+	gock.New(testURL).Post("/dataservice/template/policy/list/site").BodyString(`{"name":"SITES","type":"site"}`).Reply(200).BodyString(`{"listId":"L2"}`)
+	id, err := client.CreatePolicyList("site", Body{}.Set("name", "SITES").Set("type", "site"))
+	assert.NoError(t, err)
+	assert.Equal(t, "L2", id)
+
+	gock.New(testURL).Delete("/dataservice/template/policy/list/site/L2").Reply(200)
+	assert.NoError(t, client.DeletePolicyList("site", "L2"))
+
+	_, err = client.ListPolicyLists("site/../vpn")
+	assert.Error(t, err)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,98 @@
+package sdwan
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ClientPool is a set of clients sharing a single vManage session, see NewClientPool.
+type ClientPool struct {
+	// Clients are the clients of the pool
+	Clients []*Client
+	next    uint32
+}
+
+// poolSession is the session shared by all clients of a pool, protected by the shared AuthenticationMutex.
+type poolSession struct {
+	url    string
+	token  string
+	expiry time.Time
+	issued time.Time
+}
+
+// NewClientPool creates a pool of clients which authenticate only once and share the session.
+// All clients use the same cookie jar and token, if the session expires only one client logs in again
+// and the others take over the new token. The modifiers are applied to every client of the pool.
+// Each client keeps its own settings and limits, e.g. MaxConcurrentRequests applies per client.
+//
+//	pool, err := NewClientPool("https://10.0.0.1", "user", "password", 4, true)
+//	res, err := pool.Next().Get("/device")
+func NewClientPool(url, usr, pwd string, size int, insecure bool, mods ...func(*Client)) (*ClientPool, error) {
+	if size < 1 {
+		return nil, fmt.Errorf("pool size must be at least 1")
+	}
+	mutex := &sync.Mutex{}
+	session := &poolSession{}
+	pool := &ClientPool{}
+	for i := 0; i < size; i++ {
+		client, err := NewClient(url, usr, pwd, insecure, mods...)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			// all clients use the cookie jar of the first one, which might have been set using CookieJar
+			client.HttpClient.Jar = pool.Clients[0].HttpClient.Jar
+		}
+		client.AuthenticationMutex = mutex
+		client.session = session
+		pool.Clients = append(pool.Clients, &client)
+	}
+	if err := pool.Clients[0].Authenticate(); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// Next returns the next client of the pool in round-robin order.
+func (pool *ClientPool) Next() *Client {
+	i := atomic.AddUint32(&pool.next, 1) - 1
+	return pool.Clients[int(i%uint32(len(pool.Clients)))]
+}
+
+// adoptSession takes over the token of the shared session if the client is part of a pool and
+// another client already authenticated. Must be called with the AuthenticationMutex held.
+func (client *Client) adoptSession() bool {
+	session := client.session
+	if session == nil || session.token == "" || session.url != client.Url {
+		return false
+	}
+	if !session.expiry.IsZero() && client.clockNow().After(session.expiry) {
+		return false
+	}
+	client.Token = session.token
+	client.tokenExpiry = session.expiry
+	client.tokenIssued = session.issued
+	return true
+}
+
+// publishSession shares the token of the client with the other clients of the pool.
+// Must be called with the AuthenticationMutex held.
+func (client *Client) publishSession() {
+	if client.session == nil || client.Token == "" {
+		return
+	}
+	client.session.url = client.Url
+	client.session.token = client.Token
+	client.session.expiry = client.tokenExpiry
+	client.session.issued = client.tokenIssued
+}
+
+// invalidateSession clears the shared session if it still uses the expired token.
+// Must be called with the AuthenticationMutex held.
+func (client *Client) invalidateSession(expiredToken string) {
+	if client.session != nil && client.session.token == expiredToken {
+		client.session.token = ""
+	}
+}
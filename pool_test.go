@@ -0,0 +1,54 @@
+package sdwan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// testPoolClient intercepts the HTTP client of every client of a pool.
+func testPoolClient(client *Client) {
+	gock.InterceptClient(client.HttpClient)
+}
+
+// TestNewClientPool tests the NewClientPool function.
+func TestNewClientPool(t *testing.T) {
+	defer gock.Off()
+
+	gock.New(testURL).Post("/j_security_check").Reply(200).SetHeader("Set-Cookie", "JSESSIONID=XYZ")
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("ABC")
+	pool, err := NewClientPool(testURL, "usr", "pwd", 3, true, MaxRetries(0), LoginMaxRetries(0), testPoolClient)
+	assert.NoError(t, err)
+	assert.Len(t, pool.Clients, 3)
+	assert.True(t, gock.IsDone())
+
+	// all clients use the session of the single login, round-robin
+	gock.New(testURL).Get("/dataservice/url").MatchHeader("X-XSRF-TOKEN", "ABC").Times(3).Reply(200)
+	for i := 0; i < 3; i++ {
+		client := pool.Next()
+		assert.Same(t, pool.Clients[i], client)
+		_, err := client.Get("/url")
+		assert.NoError(t, err)
+	}
+	assert.Same(t, pool.Clients[0], pool.Next())
+	assert.Equal(t, 1, pool.Clients[0].LoginCount())
+	assert.Equal(t, 0, pool.Clients[1].LoginCount())
+	assert.True(t, gock.IsDone())
+
+	// expired session, only one client logs in again
+	gock.New(testURL).Get("/dataservice/url").MatchHeader("X-XSRF-TOKEN", "ABC").Times(2).Reply(401)
+	gock.New(testURL).Post("/j_security_check").Reply(200)
+	gock.New(testURL).Get("/dataservice/client/token").Reply(200).BodyString("DEF")
+	gock.New(testURL).Get("/dataservice/url").MatchHeader("X-XSRF-TOKEN", "DEF").Times(2).Reply(200)
+	_, err = pool.Clients[1].Get("/url")
+	assert.NoError(t, err)
+	_, err = pool.Clients[2].Get("/url")
+	assert.NoError(t, err)
+	assert.Equal(t, "DEF", pool.Clients[2].Token)
+	assert.Equal(t, 0, pool.Clients[2].LoginCount())
+	assert.True(t, gock.IsDone())
+
+	_, err = NewClientPool(testURL, "usr", "pwd", 0, true)
+	assert.Error(t, err)
+}
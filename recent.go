@@ -0,0 +1,143 @@
+package sdwan
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// recentRequest is a request/response pair kept by the RequestRecorder.
+type recentRequest struct {
+	started       time.Time
+	duration      time.Duration
+	correlationID string
+	method        string
+	url           string
+	reqHeader     http.Header
+	reqBody       string
+	status        int
+	resHeader     http.Header
+	resBody       string
+	err           error
+}
+
+// newRecentRequest creates a recorded request/response pair, sensitive headers and the payloads of
+// requests with LogPayload disabled are redacted. The response is nil if the request failed.
+func newRecentRequest(req Req, reqBody []byte, httpRes *http.Response, resBody []byte, err error, started time.Time, duration time.Duration) recentRequest {
+	entry := recentRequest{
+		started:       started,
+		duration:      duration,
+		correlationID: req.CorrelationID,
+		method:        req.HttpReq.Method,
+		url:           req.HttpReq.URL.String(),
+		reqHeader:     redactHeader(req.HttpReq.Header),
+		reqBody:       string(reqBody),
+		err:           err,
+	}
+	if httpRes != nil {
+		entry.status = httpRes.StatusCode
+		entry.resHeader = redactHeader(httpRes.Header)
+		entry.resBody = string(resBody)
+	}
+	if !req.LogPayload {
+		entry.reqBody, entry.resBody = harRedacted, harRedacted
+	}
+	return entry
+}
+
+// redactHeader returns a copy of the headers with the values of sensitive headers redacted.
+func redactHeader(header http.Header) http.Header {
+	redacted := header.Clone()
+	for name := range redacted {
+		if harSensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			redacted[name] = []string{harRedacted}
+		}
+	}
+	return redacted
+}
+
+// requestRing is a thread-safe ring buffer of the most recent requests.
+type requestRing struct {
+	mutex   sync.Mutex
+	entries []recentRequest
+	next    int
+	full    bool
+}
+
+func newRequestRing(size int) *requestRing {
+	return &requestRing{entries: make([]recentRequest, size)}
+}
+
+// add records a request, replacing the oldest one if the buffer is full.
+func (ring *requestRing) add(entry recentRequest) {
+	ring.mutex.Lock()
+	defer ring.mutex.Unlock()
+	ring.entries[ring.next] = entry
+	ring.next = (ring.next + 1) % len(ring.entries)
+	if ring.next == 0 {
+		ring.full = true
+	}
+}
+
+// snapshot returns the recorded requests from the oldest to the most recent one.
+func (ring *requestRing) snapshot() []recentRequest {
+	ring.mutex.Lock()
+	defer ring.mutex.Unlock()
+	if !ring.full {
+		return append([]recentRequest{}, ring.entries[:ring.next]...)
+	}
+	return append(append([]recentRequest{}, ring.entries[ring.next:]...), ring.entries[:ring.next]...)
+}
+
+// recordFailure records a request which failed without a response if RequestRecorder is enabled.
+func (client *Client) recordFailure(req Req, reqBody []byte, err error, started time.Time) {
+	if client.recentRequests != nil {
+		client.recentRequests.add(newRecentRequest(req, reqBody, nil, nil, err, started, client.clockNow().Sub(started)))
+	}
+}
+
+// DumpRecentRequests writes the requests kept by the RequestRecorder to w, from the oldest to the most recent one,
+// e.g. to analyze an intermittent failure after the fact. Sensitive headers and the payloads of requests with
+// LogPayload disabled are redacted. An error is returned if the RequestRecorder is not enabled.
+func (client *Client) DumpRecentRequests(w io.Writer) error {
+	if client.recentRequests == nil {
+		return fmt.Errorf("request recorder not enabled")
+	}
+	bw := bufio.NewWriter(w)
+	for _, entry := range client.recentRequests.snapshot() {
+		fmt.Fprintf(bw, "=== %s correlation_id=%s duration=%v\n", entry.started.Format(time.RFC3339Nano), entry.correlationID, entry.duration.Round(time.Millisecond))
+		fmt.Fprintf(bw, "> %s %s\n", entry.method, entry.url)
+		writeHeader(bw, "> ", entry.reqHeader)
+		if entry.reqBody != "" {
+			fmt.Fprintf(bw, ">\n> %s\n", entry.reqBody)
+		}
+		if entry.err != nil {
+			fmt.Fprintf(bw, "< error: %v\n", entry.err)
+			continue
+		}
+		fmt.Fprintf(bw, "< %d %s\n", entry.status, http.StatusText(entry.status))
+		writeHeader(bw, "< ", entry.resHeader)
+		if entry.resBody != "" {
+			fmt.Fprintf(bw, "<\n< %s\n", entry.resBody)
+		}
+	}
+	return bw.Flush()
+}
+
+// writeHeader writes headers sorted by name, one line per value.
+func writeHeader(w io.Writer, prefix string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range header[name] {
+			fmt.Fprintf(w, "%s%s: %s\n", prefix, name, value)
+		}
+	}
+}
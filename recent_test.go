@@ -0,0 +1,57 @@
+package sdwan
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientDumpRecentRequests tests the RequestRecorder modifier and the Client::DumpRecentRequests method.
+func TestClientDumpRecentRequests(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	var buf bytes.Buffer
+	assert.Error(t, client.DumpRecentRequests(&buf))
+
+	client, _ = NewClient(testURL, "usr", "pwd", true, MaxRetries(0), RequestRecorder(2))
+	gock.InterceptClient(client.HttpClient)
+	client.Token = "ABC"
+	gock.New(testURL).Get("/dataservice/first").Reply(200).BodyString(`{"a":1}`)
+	gock.New(testURL).Post("/dataservice/second").Reply(400).BodyString(`{"error":{"message":"invalid"}}`)
+	gock.New(testURL).Get("/dataservice/third").ReplyError(errors.New("connection reset"))
+	client.Get("/first")
+	client.Post("/second", `{"password":"secret"}`, NoLogPayload)
+	client.Get("/third")
+
+	assert.NoError(t, client.DumpRecentRequests(&buf))
+	dump := buf.String()
+	assert.NotContains(t, dump, "/first")
+	assert.Contains(t, dump, "> POST https://10.0.0.1/dataservice/second")
+	assert.Contains(t, dump, "> X-Xsrf-Token: REDACTED")
+	assert.Contains(t, dump, "< 400 Bad Request")
+	assert.NotContains(t, dump, "secret")
+	assert.NotContains(t, dump, "invalid")
+	assert.Contains(t, dump, "< error: Get \"https://10.0.0.1/dataservice/third\": connection reset")
+	assert.Less(t, bytes.Index(buf.Bytes(), []byte("/second")), bytes.Index(buf.Bytes(), []byte("/third")))
+
+	// Tenant session IDs are redacted
+	gock.New(testURL).Post("/dataservice/tenant/T1/vsessionid").Reply(200).BodyString(`{"VSessionId":"TENANTSESSION"}`)
+	gock.New(testURL).Get("/dataservice/fourth").Reply(200)
+	client.Get("/fourth", OnBehalfOfTenant("T1"))
+	buf.Reset()
+	assert.NoError(t, client.DumpRecentRequests(&buf))
+	assert.Contains(t, buf.String(), "> Vsessionid: REDACTED")
+	assert.NotContains(t, buf.String(), "TENANTSESSION")
+
+	// Timestamps are taken from the client clock
+	withClock(&fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)})(&client)
+	gock.New(testURL).Get("/dataservice/fifth").Reply(200)
+	client.Get("/fifth")
+	buf.Reset()
+	assert.NoError(t, client.DumpRecentRequests(&buf))
+	assert.Regexp(t, `2024-05-01T12:00:00Z correlation_id=\S* duration=0s\n> GET https://10.0.0.1/dataservice/fifth`, buf.String())
+}
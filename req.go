@@ -1,7 +1,11 @@
 package sdwan
 
 import (
+	"context"
+	"fmt"
+	"log"
 	"net/http"
+	"strings"
 
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
@@ -37,9 +41,45 @@ func (body Body) Delete(path string) Body {
 	return body
 }
 
+// Merge deep merges another body into this body.
+// Objects are merged recursively, all other values (including arrays) of the other body replace existing values.
+func (body Body) Merge(other Body) Body {
+	return body.merge("", other.Res().Result)
+}
+
+func (body Body) merge(prefix string, value gjson.Result) Body {
+	if !value.IsObject() {
+		return Body{Str: value.Raw}
+	}
+	value.ForEach(func(key, child gjson.Result) bool {
+		path := gjson.Escape(key.String())
+		if prefix != "" {
+			path = prefix + "." + path
+		}
+		if child.IsObject() && body.Res().Get(path).IsObject() {
+			body = body.merge(path, child)
+		} else {
+			body = body.SetRaw(path, child.Raw)
+		}
+		return true
+	})
+	return body
+}
+
+// Validate checks that the body is a non-empty and syntactically valid JSON document.
+func (body Body) Validate() error {
+	if body.Str == "" {
+		return fmt.Errorf("invalid JSON body: body is empty")
+	}
+	if !gjson.Valid(body.Str) {
+		return fmt.Errorf("invalid JSON body: %s", body.Str)
+	}
+	return nil
+}
+
 // Res creates a Res object, i.e. a GJSON result object.
 func (body Body) Res() Res {
-	return gjson.Parse(body.Str)
+	return newRes([]byte(body.Str))
 }
 
 // Req wraps http.Request for API requests.
@@ -48,6 +88,28 @@ type Req struct {
 	HttpReq *http.Request
 	// LogPayload indicates whether logging of payloads should be enabled.
 	LogPayload bool
+	// MaxRetries is the maximum number of retries for this request, defaults to the client setting.
+	MaxRetries int
+	// NoAuth indicates whether authentication should be skipped for this request.
+	NoAuth bool
+	// Cookies are cookies added to this request only, they take precedence over cookies of the cookie jar.
+	Cookies []*http.Cookie
+	// WaitForLock indicates whether the request should be retried while another operation holds the configuration lock.
+	WaitForLock bool
+	// Gzip indicates whether the request body is sent gzip compressed.
+	Gzip bool
+	// ExpectJSON indicates whether a successful response must have a JSON content type.
+	ExpectJSON bool
+	// CorrelationID identifies the request and its retries in log records, it is generated by Do if empty.
+	CorrelationID string
+	// Priority determines the order in which requests waiting for a slot are sent if MaxConcurrentRequests is set.
+	Priority int
+	// Tenant is the ID of the tenant a provider request is made on behalf of, see OnBehalfOfTenant.
+	Tenant string
+	// BodyTransform is applied once to the request body before it is sent, see BodyTransform.
+	BodyTransform func(body []byte) ([]byte, error)
+	// IgnoreJSONError indicates whether an error object in a successful response is ignored, see IgnoreJSONError.
+	IgnoreJSONError bool
 }
 
 // NoLogPayload prevents logging of payloads.
@@ -55,3 +117,162 @@ type Req struct {
 func NoLogPayload(req *Req) {
 	req.LogPayload = false
 }
+
+// NoAuth skips the authentication and the XSRF token header for a request.
+// Primarily used for unauthenticated endpoints, e.g. health checks before credentials are set.
+func NoAuth() func(*Req) {
+	return func(req *Req) {
+		req.NoAuth = true
+	}
+}
+
+// Accept sets the Accept header of a request, e.g. "text/csv".
+// Responses of non-JSON media types are not parsed, use Res.Bytes to access the response body.
+func Accept(mediaType string) func(*Req) {
+	return func(req *Req) {
+		req.HttpReq.Header.Set("Accept", mediaType)
+	}
+}
+
+// Query sets a query parameter of a request, e.g. Query("deviceId", "10.0.0.1").
+// Parameters already contained in the path are kept unless they have the same key.
+func Query(key, value string) func(*Req) {
+	return func(req *Req) {
+		query := req.HttpReq.URL.Query()
+		query.Set(key, value)
+		req.HttpReq.URL.RawQuery = query.Encode()
+	}
+}
+
+// Fields limits the response to the given attributes using the "fields" query parameter of vManage,
+// e.g. Fields([]string{"deviceId", "host-name"}). This reduces the response size of large device or statistics lists.
+func Fields(fields []string) func(*Req) {
+	return Query("fields", strings.Join(fields, ","))
+}
+
+// WithCookie adds a cookie to a single request.
+// A cookie of the cookie jar with the same name, e.g. JSESSIONID, is not sent along with the request.
+func WithCookie(cookie *http.Cookie) func(*Req) {
+	return func(req *Req) {
+		req.Cookies = append(req.Cookies, cookie)
+		req.HttpReq.AddCookie(cookie)
+	}
+}
+
+// WaitForLock retries a request while vManage rejects it because another operation holds the configuration lock,
+// e.g. a concurrent template attachment. The request is retried until the lock is released or the
+// LockWaitTimeout of the client expires.
+func WaitForLock(x bool) func(*Req) {
+	return func(req *Req) {
+		req.WaitForLock = x
+	}
+}
+
+// GzipRequest compresses the request body and sets the "Content-Encoding: gzip" header, e.g. for large configuration pushes.
+// If vManage does not accept compressed bodies (HTTP status code 415), the request is sent again uncompressed.
+func GzipRequest(x bool) func(*Req) {
+	return func(req *Req) {
+		req.Gzip = x
+	}
+}
+
+// ExpectJSON verifies that a successful response has a JSON content type, otherwise ErrUnexpectedContentType
+// is returned along with the beginning of the response body. This detects requests which are answered
+// with an HTML page, e.g. because of a wrong path, instead of silently returning an empty result.
+func ExpectJSON(x bool) func(*Req) {
+	return func(req *Req) {
+		req.ExpectJSON = x
+	}
+}
+
+// IgnoreJSONError returns a successful response as-is even if it contains an error object ("error.code").
+// Some endpoints embed advisory error objects in successful responses, which would otherwise fail the request.
+func IgnoreJSONError() func(*Req) {
+	return func(req *Req) {
+		req.IgnoreJSONError = true
+	}
+}
+
+// Priority sets the priority of a request if the number of concurrent requests is limited (see MaxConcurrentRequests).
+// Waiting requests with a higher priority are sent first, e.g. interactive reads ahead of bulk writes. The default is 0.
+func Priority(x int) func(*Req) {
+	return func(req *Req) {
+		req.Priority = x
+	}
+}
+
+// CorrelationID sets the correlation ID of a request, e.g. to use the ID of an operation of the caller.
+func CorrelationID(id string) func(*Req) {
+	return func(req *Req) {
+		req.CorrelationID = id
+	}
+}
+
+// logf logs a record of the request including its correlation ID.
+func (req Req) logf(format string, v ...interface{}) {
+	if req.CorrelationID == "" {
+		log.Printf(format, v...)
+		return
+	}
+	log.Printf(format+" correlation_id=%s", append(v, req.CorrelationID)...)
+}
+
+// Retries overrides the maximum number of retries of the client for a single request.
+// A value of 0 means exactly one attempt, negative values are treated as 0.
+func Retries(x int) func(*Req) {
+	return func(req *Req) {
+		req.MaxRetries = nonNegative(x)
+	}
+}
+
+// Prefix replaces the "/dataservice" path prefix added by Get, Post, Put and Delete (and the functions built on top of them),
+// e.g. to reach API groups exposed by vManage outside of the REST API:
+//
+//	client.Get("/apidocs", sdwan.NoPrefix)                 // https://vmanage/apidocs
+//	client.Get("/status", sdwan.Prefix("/custom-api"))     // https://vmanage/custom-api/status
+//
+// The REST API (/dataservice) covers almost all endpoints including vBond and vSmart related operations,
+// which are proxied by vManage. Authentication endpoints (/j_security_check, /logout) and static content
+// like the API documentation (/apidocs) are served without prefix.
+func Prefix(prefix string) func(*Req) {
+	return func(req *Req) {
+		if i := strings.Index(req.HttpReq.URL.Path, "/dataservice"); i >= 0 {
+			req.HttpReq.URL.Path = req.HttpReq.URL.Path[:i] + prefix + req.HttpReq.URL.Path[i+len("/dataservice"):]
+			req.HttpReq.URL.RawPath = ""
+		}
+	}
+}
+
+// NoPrefix removes the "/dataservice" path prefix, see Prefix.
+func NoPrefix(req *Req) {
+	Prefix("")(req)
+}
+
+// Node sends a request to a specific vManage cluster member, e.g. "10.0.0.2" or "10.0.0.2:8443".
+// In a cluster, a read following a write might hit a member which has not yet been updated,
+// pinning both requests to the same member avoids such inconsistencies.
+// The cookie jar stores cookies per host, therefore the session cookies of the client URL
+// are sent along with the request, the session must be valid on the targeted member.
+func Node(node string) func(*Req) {
+	return func(req *Req) {
+		req.HttpReq.URL.Host = node
+		req.HttpReq.Host = node
+	}
+}
+
+// Context sets the context of a request, e.g. to cancel it or to apply a deadline.
+// Once the context is done, the request is neither retried nor failed over and returns the context error.
+func Context(ctx context.Context) func(*Req) {
+	return func(req *Req) {
+		req.HttpReq = req.HttpReq.WithContext(ctx)
+	}
+}
+
+// BodyTransform sets a function which modifies the request body before it is sent, e.g. to strip attributes
+// rejected by certain vManage versions or to redact values. The function is applied once and the transformed
+// body is logged and sent on every retry. If it fails, the error is returned without sending the request.
+func BodyTransform(fn func(body []byte) ([]byte, error)) func(*Req) {
+	return func(req *Req) {
+		req.BodyTransform = fn
+	}
+}
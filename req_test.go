@@ -20,3 +20,24 @@ func TestDelete(t *testing.T) {
 	body = body.Delete("a.name")
 	assert.Equal(t, "", body.Res().Get("a.name").Str)
 }
+
+// TestValidate tests the Body::Validate method.
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Body{}.Set("name", "a").Validate())
+	assert.Error(t, Body{}.Validate())
+	assert.Error(t, Body{Str: `{"name":`}.Validate())
+}
+
+// TestMerge tests the Body::Merge method.
+func TestMerge(t *testing.T) {
+	body := Body{Str: `{"name":"a","settings":{"x":1,"y":2},"list":[1,2]}`}
+	patch := Body{Str: `{"settings":{"y":3,"z.z":4},"list":[3]}`}
+	assert.Equal(t, `{"name":"a","settings":{"x":1,"y":3,"z.z":4},"list":[3]}`, body.Merge(patch).Str)
+}
+
+// TestQuery tests the Query and Fields modifiers.
+func TestQuery(t *testing.T) {
+	client, _ := NewClient(testURL, "usr", "pwd", true)
+	req := client.NewReq("GET", "/dataservice/device?reachability=reachable", nil, Fields([]string{"deviceId", "host-name"}), Query("site-id", "100"))
+	assert.Equal(t, "fields=deviceId%2Chost-name&reachability=reachable&site-id=100", req.HttpReq.URL.RawQuery)
+}
@@ -1,10 +1,195 @@
 package sdwan
 
 import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
 	"github.com/tidwall/gjson"
 )
 
 // Res is an API response returned by client requests.
 // This is a GJSON result, which offers advanced and safe parsing capabilities.
 // https://github.com/tidwall/gjson
-type Res = gjson.Result
+// In addition to the GJSON result, the unmodified response body is retained.
+type Res struct {
+	gjson.Result
+	// Empty indicates a successful response without a body.
+	Empty bool
+	// CorrelationID is the correlation ID of the request, see Do.
+	CorrelationID string
+	// Header contains the response headers, nil if no response has been received.
+	Header http.Header
+	body   []byte
+}
+
+// newRes creates a Res object from a raw response body.
+func newRes(body []byte) Res {
+	return Res{Result: gjson.ParseBytes(body), body: body}
+}
+
+// toRes wraps a GJSON result, e.g. an element of an array within a response, in a Res object.
+func toRes(result gjson.Result) Res {
+	return Res{Result: result}
+}
+
+// toResArray wraps a slice of GJSON results in Res objects.
+func toResArray(results []gjson.Result) []Res {
+	array := make([]Res, len(results))
+	for i, result := range results {
+		array[i] = toRes(result)
+	}
+	return array
+}
+
+// IsEmpty checks whether the response was successful but without a body, e.g. for some DELETE or PUT operations.
+func (res Res) IsEmpty() bool {
+	return res.Empty
+}
+
+// Bytes returns the unmodified response body, e.g. for non-JSON responses like CSV exports.
+func (res Res) Bytes() []byte {
+	if res.body == nil {
+		return []byte(res.Raw)
+	}
+	return res.body
+}
+
+// ErrorDetail is a single structured entry of the "error.details" array of a vManage error response.
+type ErrorDetail struct {
+	Code    string
+	Message string
+	Type    string
+}
+
+// String formats an error detail, e.g. "DEVICE_NOT_FOUND: Device not found (error)".
+func (detail ErrorDetail) String() string {
+	s := detail.Message
+	if detail.Code != "" {
+		s = detail.Code + ": " + s
+	}
+	if detail.Type != "" {
+		s = s + " (" + detail.Type + ")"
+	}
+	return s
+}
+
+// ErrorDetails returns the structured error details of a vManage error response.
+// Nil is returned if "error.details" is absent or not an array.
+func (res Res) ErrorDetails() []ErrorDetail {
+	details := res.Get("error.details")
+	if !details.IsArray() {
+		return nil
+	}
+	var errorDetails []ErrorDetail
+	for _, detail := range details.Array() {
+		errorDetails = append(errorDetails, ErrorDetail{
+			Code:    detail.Get("code").String(),
+			Message: detail.Get("message").String(),
+			Type:    detail.Get("type").String(),
+		})
+	}
+	return errorDetails
+}
+
+// errorDetailsString formats all error details of a response, e.g. "[CODE1: message1, CODE2: message2]".
+func (res Res) errorDetailsString() string {
+	var details []string
+	for _, detail := range res.ErrorDetails() {
+		details = append(details, detail.String())
+	}
+	if len(details) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(details, ", ") + "]"
+}
+
+// GetErr retrieves the value of a path like Get, but returns an error wrapping ErrNotFound if the path is absent.
+func (res Res) GetErr(path string) (Res, error) {
+	result := res.Get(path)
+	if !result.Exists() {
+		return Res{}, fmt.Errorf("path %s: %w", path, ErrNotFound)
+	}
+	return toRes(result), nil
+}
+
+// MustGet retrieves the value of a path like Get, but panics if the path is absent.
+// It is meant for tests, where a missing attribute should fail loudly instead of returning a zero value.
+func (res Res) MustGet(path string) Res {
+	result, err := res.GetErr(path)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
+// Paths returns the paths of all leaf values (including empty objects and arrays) in document order,
+// e.g. ["data.0.deviceId", "data.0.host-name"]. This is useful for debugging unexpected response shapes.
+func (res Res) Paths() []string {
+	var paths []string
+	walkLeaves(res.Result, "", func(path string, value gjson.Result) {
+		paths = append(paths, path)
+	})
+	return paths
+}
+
+// walkLeaves calls fn for all leaf values (including empty objects and arrays) of a GJSON result below a given prefix.
+func walkLeaves(result gjson.Result, prefix string, fn func(path string, value gjson.Result)) {
+	if !result.IsObject() && !result.IsArray() {
+		if result.Exists() && prefix != "" {
+			fn(prefix, result)
+		}
+		return
+	}
+	leaf := true
+	index := 0
+	result.ForEach(func(key, value gjson.Result) bool {
+		leaf = false
+		var name string
+		if result.IsArray() {
+			name = strconv.Itoa(index)
+			index++
+		} else {
+			name = gjson.Escape(key.String())
+		}
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+		walkLeaves(value, name, fn)
+		return true
+	})
+	if leaf && prefix != "" {
+		fn(prefix, result)
+	}
+}
+
+// PageInfo is the pagination metadata of a list response.
+type PageInfo struct {
+	// Count is the number of entries of the current page.
+	Count int
+	// StartID is the ID of the first entry of the current page.
+	StartID string
+	// EndID is the ID of the last entry of the current page, used to request the next page.
+	EndID string
+	// MoreEntries indicates whether further pages are available.
+	MoreEntries bool
+	// ScrollID is the ID used to request the next page of scrolled queries, e.g. statistics.
+	ScrollID string
+}
+
+// PageInfo returns the pagination metadata ("pageInfo") of a response and whether it is present.
+// Both "moreEntries" and "hasMoreData" (used by scrolled queries) are reported as MoreEntries.
+func (res Res) PageInfo() (PageInfo, bool) {
+	pageInfo := res.Get("pageInfo")
+	if !pageInfo.IsObject() {
+		return PageInfo{}, false
+	}
+	return PageInfo{
+		Count:       int(pageInfo.Get("count").Int()),
+		StartID:     pageInfo.Get("startId").String(),
+		EndID:       pageInfo.Get("endId").String(),
+		MoreEntries: pageInfo.Get("moreEntries").Bool() || pageInfo.Get("hasMoreData").Bool(),
+		ScrollID:    pageInfo.Get("scrollId").String(),
+	}, true
+}
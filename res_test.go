@@ -0,0 +1,97 @@
+package sdwan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestResBytes tests the Res::Bytes method.
+func TestResBytes(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	csv := "hostname,system-ip\nedge1,1.1.1.1\n"
+	gock.New(testURL).Get("/dataservice/export").Reply(200).BodyString(csv)
+	res, err := client.Get("/export")
+	assert.NoError(t, err)
+	assert.Equal(t, csv, string(res.Bytes()))
+
+	// Non-JSON responses are not parsed
+	gock.New(testURL).Get("/dataservice/export").MatchHeader("Accept", "text/csv").Reply(200).BodyString(csv)
+	res, err = client.Get("/export", Accept("text/csv"))
+	assert.NoError(t, err)
+	assert.Equal(t, csv, string(res.Bytes()))
+	assert.Equal(t, "", res.Raw)
+
+	// Nested results fall back to the raw JSON
+	assert.Equal(t, `{"b":1}`, string(toRes(Body{}.SetRaw("a", `{"b":1}`).Res().Get("a")).Bytes()))
+}
+
+// TestResErrorDetails tests the Res::ErrorDetails method.
+func TestResErrorDetails(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).
+		Post("/dataservice/url").
+		Reply(200).
+		BodyString(`{"error":{"code":"BATCH","message":"Batch failed","details":[{"code":"E1","message":"First","type":"error"},{"code":"E2","message":"Second"}]}}`)
+	res, err := client.Post("/url", "{}")
+	assert.ErrorContains(t, err, "[E1: First (error), E2: Second]")
+	assert.Equal(t, []ErrorDetail{{Code: "E1", Message: "First", Type: "error"}, {Code: "E2", Message: "Second"}}, res.ErrorDetails())
+
+	// Details not structured
+	assert.Nil(t, Body{}.Set("error.details", "text").Res().ErrorDetails())
+}
+
+// TestResIsEmpty tests the Res::IsEmpty method.
+func TestResIsEmpty(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Delete("/dataservice/url").Reply(200)
+	res, err := client.Delete("/url")
+	assert.NoError(t, err)
+	assert.True(t, res.IsEmpty())
+
+	gock.New(testURL).Delete("/dataservice/url").Reply(200).BodyString(`{"id":"1"}`)
+	res, err = client.Delete("/url")
+	assert.NoError(t, err)
+	assert.False(t, res.IsEmpty())
+}
+
+// TestResMustGet tests the Res::MustGet and Res::GetErr methods.
+func TestResMustGet(t *testing.T) {
+	res := newRes([]byte(`{"data":[{"deviceId":"1.1.1.1"}]}`))
+
+	assert.Equal(t, "1.1.1.1", res.MustGet("data.0.deviceId").String())
+	assert.Panics(t, func() { res.MustGet("data.0.hostName") })
+	_, err := res.GetErr("data.0.hostName")
+	assert.True(t, errors.Is(err, ErrNotFound))
+}
+
+// TestResPaths tests the Res::Paths method.
+func TestResPaths(t *testing.T) {
+	res := newRes([]byte(`{"header":{},"data":[{"deviceId":"1.1.1.1","host-name":"R1"},[]],"a.b":null}`))
+
+	assert.Equal(t, []string{"header", "data.0.deviceId", "data.0.host-name", "data.1", `a\.b`}, res.Paths())
+	assert.True(t, res.Get(res.Paths()[4]).Exists())
+}
+
+// TestResPageInfo tests the Res::PageInfo method.
+func TestResPageInfo(t *testing.T) {
+	res := newRes([]byte(`{"data":[],"pageInfo":{"count":2,"startId":"1","endId":"2","moreEntries":true}}`))
+	pageInfo, ok := res.PageInfo()
+	assert.True(t, ok)
+	assert.Equal(t, PageInfo{Count: 2, StartID: "1", EndID: "2", MoreEntries: true}, pageInfo)
+
+	res = newRes([]byte(`{"pageInfo":{"scrollId":"S1","hasMoreData":true}}`))
+	pageInfo, _ = res.PageInfo()
+	assert.Equal(t, PageInfo{ScrollID: "S1", MoreEntries: true}, pageInfo)
+
+	_, ok = newRes([]byte(`{"data":[]}`)).PageInfo()
+	assert.False(t, ok)
+}
@@ -0,0 +1,279 @@
+package sdwan
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/tidwall/gjson"
+)
+
+// defaultResolveConcurrency is the maximum number of concurrent requests of Client.ResolveDeviceTemplate.
+const defaultResolveConcurrency = 4
+
+// ResolvedFeatureTemplate is a feature template referenced by a device template, see ResolveDeviceTemplate.
+type ResolvedFeatureTemplate struct {
+	// ID is the ID of the feature template.
+	ID string
+	// Type is the template type, e.g. "cisco_system" or "cisco_vpn".
+	Type string
+	// Template is the complete feature template object.
+	Template Res
+	// SubTemplates are the resolved sub-templates, e.g. the interface templates of a VPN template.
+	SubTemplates []ResolvedFeatureTemplate
+}
+
+// ResolvedPolicy is a policy referenced by a device template, see ResolveDeviceTemplate.
+type ResolvedPolicy struct {
+	// ID is the ID of the policy.
+	ID string
+	// Policy is the complete policy object.
+	Policy Res
+	// Definitions are the policy definitions in the order of the policy assembly.
+	Definitions []Res
+}
+
+// ResolvedTemplate is a device template including all objects it references, see ResolveDeviceTemplate.
+type ResolvedTemplate struct {
+	// ID is the ID of the device template.
+	ID string
+	// Template is the complete device template object.
+	Template Res
+	// FeatureTemplates are the resolved feature templates in the order of the device template.
+	FeatureTemplates []ResolvedFeatureTemplate
+	// Policy is the localized policy, nil if the device template has none.
+	Policy *ResolvedPolicy
+	// SecurityPolicy is the security policy, nil if the device template has none.
+	SecurityPolicy *ResolvedPolicy
+	// Lists are the policy lists referenced by the policy definitions, mapped by their IDs.
+	Lists map[string]Res
+}
+
+// TemplateResolver resolves device templates with a bounded number of concurrent requests.
+// Retrieved objects are cached for the lifetime of the resolver, objects shared by multiple device templates,
+// e.g. common feature templates, are therefore only retrieved once. Create a new resolver to discard the cache.
+type TemplateResolver struct {
+	client *Client
+	slots  chan struct{}
+	mutex  sync.Mutex
+	cache  map[string]*resolverEntry
+}
+
+// resolverEntry is a cached object of a TemplateResolver, done is closed once it has been retrieved.
+type resolverEntry struct {
+	done chan struct{}
+	res  Res
+	err  error
+}
+
+// NewTemplateResolver creates a TemplateResolver sending at most concurrency requests at a time.
+//
+//	resolver := client.NewTemplateResolver(8)
+//	for _, id := range templateIDs {
+//		resolved, err := resolver.ResolveDeviceTemplate(id)
+//	}
+func (client *Client) NewTemplateResolver(concurrency int) *TemplateResolver {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	return &TemplateResolver{
+		client: client,
+		slots:  make(chan struct{}, concurrency),
+		cache:  map[string]*resolverEntry{},
+	}
+}
+
+// ResolveDeviceTemplate retrieves a device template and all objects it references: the feature templates
+// (including sub-templates), the localized and security policies with their definitions, and the policy lists
+// referenced by the definitions. At most 4 requests are sent concurrently, use NewTemplateResolver to change
+// the limit or to cache objects across multiple device templates.
+func (client *Client) ResolveDeviceTemplate(templateID string, mods ...func(*Req)) (ResolvedTemplate, error) {
+	return client.NewTemplateResolver(defaultResolveConcurrency).ResolveDeviceTemplate(templateID, mods...)
+}
+
+// ResolveDeviceTemplate retrieves a device template and all objects it references, see Client.ResolveDeviceTemplate.
+func (resolver *TemplateResolver) ResolveDeviceTemplate(templateID string, mods ...func(*Req)) (ResolvedTemplate, error) {
+	template, err := resolver.get("/template/device/object/"+url.PathEscape(templateID), mods...)
+	if err != nil {
+		return ResolvedTemplate{}, fmt.Errorf("device template %s: %w", templateID, err)
+	}
+	resolved := ResolvedTemplate{ID: templateID, Template: template, Lists: map[string]Res{}}
+
+	var wg sync.WaitGroup
+	errs := make([]error, 3)
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		resolved.FeatureTemplates, errs[0] = resolver.resolveFeatureTemplates(template.Get("generalTemplates").Array(), mods...)
+	}()
+	go func() {
+		defer wg.Done()
+		resolved.Policy, errs[1] = resolver.resolvePolicy("/template/policy/vedge/definition/", template.Get("policyId").String(), mods...)
+	}()
+	go func() {
+		defer wg.Done()
+		resolved.SecurityPolicy, errs[2] = resolver.resolvePolicy("/template/policy/security/definition/", template.Get("securityPolicyId").String(), mods...)
+	}()
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return ResolvedTemplate{}, fmt.Errorf("device template %s: %w", templateID, err)
+		}
+	}
+
+	refs := []string{}
+	for _, policy := range []*ResolvedPolicy{resolved.Policy, resolved.SecurityPolicy} {
+		if policy == nil {
+			continue
+		}
+		for _, definition := range policy.Definitions {
+			refs = append(refs, listRefs(definition.Result)...)
+		}
+	}
+	if len(refs) == 0 {
+		return resolved, nil
+	}
+	lists, err := resolver.get("/template/policy/list", mods...)
+	if err != nil {
+		return ResolvedTemplate{}, fmt.Errorf("device template %s: policy lists: %w", templateID, err)
+	}
+	listsByID := map[string]gjson.Result{}
+	for _, list := range lists.Get("data").Array() {
+		listsByID[list.Get("listId").String()] = list
+	}
+	for _, ref := range refs {
+		list, ok := listsByID[ref]
+		if !ok {
+			// references of other definitions are not resolved
+			log.Printf("[DEBUG] Policy reference %s of device template %s is not a list", ref, templateID)
+			continue
+		}
+		resolved.Lists[ref] = newRes([]byte(list.Raw))
+	}
+	return resolved, nil
+}
+
+// resolveFeatureTemplates concurrently retrieves the feature templates of a list of template references
+// ("generalTemplates" or "subTemplates") and their sub-templates.
+func (resolver *TemplateResolver) resolveFeatureTemplates(refs []gjson.Result, mods ...func(*Req)) ([]ResolvedFeatureTemplate, error) {
+	templates := make([]ResolvedFeatureTemplate, len(refs))
+	errs := make([]error, len(refs))
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		go func(i int, ref gjson.Result) {
+			defer wg.Done()
+			id := ref.Get("templateId").String()
+			template, err := resolver.get("/template/feature/object/"+url.PathEscape(id), mods...)
+			if err != nil {
+				errs[i] = fmt.Errorf("feature template %s: %w", id, err)
+				return
+			}
+			subTemplates, err := resolver.resolveFeatureTemplates(ref.Get("subTemplates").Array(), mods...)
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			templates[i] = ResolvedFeatureTemplate{
+				ID:           id,
+				Type:         ref.Get("templateType").String(),
+				Template:     template,
+				SubTemplates: subTemplates,
+			}
+		}(i, ref)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return templates, nil
+}
+
+// resolvePolicy retrieves a policy and the definitions of its assembly, it returns nil if the policy ID is empty.
+func (resolver *TemplateResolver) resolvePolicy(path, policyID string, mods ...func(*Req)) (*ResolvedPolicy, error) {
+	if policyID == "" {
+		return nil, nil
+	}
+	policy, err := resolver.get(path+url.PathEscape(policyID), mods...)
+	if err != nil {
+		return nil, fmt.Errorf("policy %s: %w", policyID, err)
+	}
+	// CLI policies have no assembly
+	assembly := policy.Get("policyDefinition.assembly").Array()
+	definitions := make([]Res, len(assembly))
+	errs := make([]error, len(assembly))
+	var wg sync.WaitGroup
+	for i, entry := range assembly {
+		wg.Add(1)
+		go func(i int, entry gjson.Result) {
+			defer wg.Done()
+			id := entry.Get("definitionId").String()
+			definitionType := strings.ToLower(entry.Get("type").String())
+			definitions[i], errs[i] = resolver.get("/template/policy/definition/"+url.PathEscape(definitionType)+"/"+url.PathEscape(id), mods...)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("policy %s: %s definition %s: %w", policyID, definitionType, id, errs[i])
+			}
+		}(i, entry)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &ResolvedPolicy{ID: policyID, Policy: policy, Definitions: definitions}, nil
+}
+
+// get retrieves an object, limiting the number of concurrent requests. Objects are cached, concurrent calls for
+// the same path wait for the first one. Failures are not cached.
+func (resolver *TemplateResolver) get(path string, mods ...func(*Req)) (Res, error) {
+	resolver.mutex.Lock()
+	entry, ok := resolver.cache[path]
+	if !ok {
+		entry = &resolverEntry{done: make(chan struct{})}
+		resolver.cache[path] = entry
+	}
+	resolver.mutex.Unlock()
+	if ok {
+		<-entry.done
+		return entry.res, entry.err
+	}
+
+	resolver.slots <- struct{}{}
+	entry.res, entry.err = resolver.client.Get(path, mods...)
+	<-resolver.slots
+	if entry.err != nil {
+		resolver.mutex.Lock()
+		delete(resolver.cache, path)
+		resolver.mutex.Unlock()
+	}
+	close(entry.done)
+	return entry.res, entry.err
+}
+
+// listRefs returns the values of all "ref" attributes of a policy definition in the order they appear,
+// which are the IDs of the referenced policy lists (or definitions).
+func listRefs(definition gjson.Result) []string {
+	refs := []string{}
+	seen := map[string]bool{}
+	var walk func(value gjson.Result)
+	walk = func(value gjson.Result) {
+		value.ForEach(func(key, child gjson.Result) bool {
+			if key.String() == "ref" && child.Type == gjson.String {
+				if !seen[child.String()] {
+					seen[child.String()] = true
+					refs = append(refs, child.String())
+				}
+			} else if child.IsObject() || child.IsArray() {
+				walk(child)
+			}
+			return true
+		})
+	}
+	walk(definition)
+	return refs
+}
@@ -0,0 +1,53 @@
+package sdwan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientResolveDeviceTemplate tests the Client::ResolveDeviceTemplate method.
+func TestClientResolveDeviceTemplate(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/template/device/object/D1").Reply(200).BodyString(`{"templateName":"edge","policyId":"P1","securityPolicyId":"",` +
+		`"generalTemplates":[{"templateId":"F1","templateType":"cisco_system"},{"templateId":"F2","templateType":"cisco_vpn","subTemplates":[{"templateId":"F3","templateType":"cisco_vpn_interface"}]}]}`)
+	gock.New(testURL).Get("/dataservice/template/feature/object/F1").Reply(200).BodyString(`{"templateName":"system"}`)
+	gock.New(testURL).Get("/dataservice/template/feature/object/F2").Reply(200).BodyString(`{"templateName":"vpn"}`)
+	gock.New(testURL).Get("/dataservice/template/feature/object/F3").Reply(200).BodyString(`{"templateName":"interface"}`)
+	gock.New(testURL).Get("/dataservice/template/policy/vedge/definition/P1").Reply(200).
+		BodyString(`{"policyName":"local","policyDefinition":{"assembly":[{"definitionId":"A1","type":"acl"}]}}`)
+	gock.New(testURL).Get("/dataservice/template/policy/definition/acl/A1").Reply(200).
+		BodyString(`{"name":"acl","sequences":[{"match":{"entries":[{"field":"sourceDataPrefixList","ref":"L1"},{"field":"class","ref":"C1"}]}}]}`)
+	gock.New(testURL).Get("/dataservice/template/policy/list").Reply(200).BodyString(`{"data":[{"listId":"L1","name":"PREFIXES"},{"listId":"L2"}]}`)
+
+	resolver := client.NewTemplateResolver(2)
+	resolved, err := resolver.ResolveDeviceTemplate("D1")
+	assert.NoError(t, err)
+	assert.Equal(t, "edge", resolved.Template.Get("templateName").String())
+	assert.Len(t, resolved.FeatureTemplates, 2)
+	assert.Equal(t, "cisco_system", resolved.FeatureTemplates[0].Type)
+	assert.Equal(t, "interface", resolved.FeatureTemplates[1].SubTemplates[0].Template.Get("templateName").String())
+	assert.Equal(t, "local", resolved.Policy.Policy.Get("policyName").String())
+	assert.Len(t, resolved.Policy.Definitions, 1)
+	assert.Nil(t, resolved.SecurityPolicy)
+	assert.Len(t, resolved.Lists, 1)
+	assert.Equal(t, "PREFIXES", resolved.Lists["L1"].Get("name").String())
+	assert.True(t, gock.IsDone())
+
+	// Shared feature templates are cached
+	gock.New(testURL).Get("/dataservice/template/device/object/D2").Reply(200).BodyString(`{"generalTemplates":[{"templateId":"F1"}]}`)
+	resolved, err = resolver.ResolveDeviceTemplate("D2")
+	assert.NoError(t, err)
+	assert.Equal(t, "system", resolved.FeatureTemplates[0].Template.Get("templateName").String())
+	assert.True(t, gock.IsDone())
+
+	// Missing feature template
+	gock.New(testURL).Get("/dataservice/template/device/object/D3").Reply(200).BodyString(`{"generalTemplates":[{"templateId":"F4"}]}`)
+	gock.New(testURL).Get("/dataservice/template/feature/object/F4").Reply(404)
+	_, err = client.ResolveDeviceTemplate("D3", Retries(0))
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.ErrorContains(t, err, "feature template F4")
+}
@@ -0,0 +1,103 @@
+package sdwan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// SchemaType is the expected JSON type of a response attribute, see Schema.
+type SchemaType int
+
+// JSON types of response attributes.
+const (
+	// SchemaAny only requires the attribute to exist.
+	SchemaAny SchemaType = iota
+	SchemaString
+	SchemaNumber
+	SchemaBool
+	SchemaObject
+	SchemaArray
+)
+
+// String returns the name of the JSON type.
+func (t SchemaType) String() string {
+	switch t {
+	case SchemaString:
+		return "string"
+	case SchemaNumber:
+		return "number"
+	case SchemaBool:
+		return "bool"
+	case SchemaObject:
+		return "object"
+	case SchemaArray:
+		return "array"
+	}
+	return "any"
+}
+
+// matches checks whether a GJSON result is of the type.
+func (t SchemaType) matches(result gjson.Result) bool {
+	switch t {
+	case SchemaString:
+		return result.Type == gjson.String
+	case SchemaNumber:
+		return result.Type == gjson.Number
+	case SchemaBool:
+		return result.Type == gjson.True || result.Type == gjson.False
+	case SchemaObject:
+		return result.IsObject()
+	case SchemaArray:
+		return result.IsArray()
+	}
+	return true
+}
+
+// resultType returns the name of the JSON type of a GJSON result.
+func resultType(result gjson.Result) string {
+	switch {
+	case result.IsObject():
+		return "object"
+	case result.IsArray():
+		return "array"
+	case result.Type == gjson.True || result.Type == gjson.False:
+		return "bool"
+	case result.Type == gjson.Number:
+		return "number"
+	case result.Type == gjson.Null:
+		return "null"
+	}
+	return "string"
+}
+
+// Schema describes the expected shape of a response as required GJSON paths and their types, e.g.
+//
+//	Schema{"data": SchemaArray, "data.0.uuid": SchemaString, "pageInfo.moreEntries": SchemaBool}
+type Schema map[string]SchemaType
+
+// Validate checks whether the response matches a schema, i.e. all paths of the schema exist and have the expected
+// types. This detects responses changed by other vManage releases instead of silently processing missing values.
+// The returned error wraps ErrUnexpectedSchema and lists all mismatches.
+func (res Res) Validate(schema Schema) error {
+	paths := make([]string, 0, len(schema))
+	for path := range schema {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	var mismatches []string
+	for _, path := range paths {
+		result := res.Get(path)
+		if !result.Exists() {
+			mismatches = append(mismatches, fmt.Sprintf("%s missing", path))
+		} else if expected := schema[path]; !expected.matches(result) {
+			mismatches = append(mismatches, fmt.Sprintf("%s is %s, expected %s", path, resultType(result), expected))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("%w: %s", ErrUnexpectedSchema, strings.Join(mismatches, ", "))
+	}
+	return nil
+}
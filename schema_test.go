@@ -0,0 +1,25 @@
+package sdwan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestResValidate tests the Res::Validate method.
+func TestResValidate(t *testing.T) {
+	res := newRes([]byte(`{"data":[{"uuid":"D1","reachable":true,"count":1}],"header":{}}`))
+	assert.NoError(t, res.Validate(Schema{
+		"data":             SchemaArray,
+		"data.0.uuid":      SchemaString,
+		"data.0.reachable": SchemaBool,
+		"data.0.count":     SchemaNumber,
+		"header":           SchemaObject,
+		"data.0":           SchemaAny,
+	}))
+
+	err := res.Validate(Schema{"data": SchemaObject, "pageInfo": SchemaAny, "data.0.count": SchemaNumber})
+	assert.True(t, errors.Is(err, ErrUnexpectedSchema))
+	assert.EqualError(t, err, "unexpected response schema, possibly unsupported vManage version: data is array, expected object, pageInfo missing")
+}
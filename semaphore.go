@@ -0,0 +1,83 @@
+package sdwan
+
+import (
+	"context"
+	"sync"
+)
+
+// prioritySemaphore limits the number of requests in flight. Waiting requests acquire a slot by
+// descending priority, requests of the same priority in the order they started waiting.
+type prioritySemaphore struct {
+	mutex   sync.Mutex
+	size    int
+	used    int
+	waiters []*semaphoreWaiter
+}
+
+// semaphoreWaiter is a request waiting for a slot, ready is closed once the slot is handed over.
+type semaphoreWaiter struct {
+	priority int
+	ready    chan struct{}
+}
+
+// newPrioritySemaphore creates a semaphore with a given number of slots.
+func newPrioritySemaphore(size int) *prioritySemaphore {
+	return &prioritySemaphore{size: size}
+}
+
+// acquire blocks until a slot is available or the context is canceled.
+func (s *prioritySemaphore) acquire(ctx context.Context, priority int) error {
+	s.mutex.Lock()
+	if s.used < s.size && len(s.waiters) == 0 {
+		s.used++
+		s.mutex.Unlock()
+		return nil
+	}
+	waiter := &semaphoreWaiter{priority: priority, ready: make(chan struct{})}
+	i := len(s.waiters)
+	for i > 0 && s.waiters[i-1].priority < priority {
+		i--
+	}
+	s.waiters = append(s.waiters, nil)
+	copy(s.waiters[i+1:], s.waiters[i:])
+	s.waiters[i] = waiter
+	s.mutex.Unlock()
+
+	select {
+	case <-waiter.ready:
+		return nil
+	case <-ctx.Done():
+		s.mutex.Lock()
+		granted := false
+		select {
+		case <-waiter.ready:
+			granted = true
+		default:
+			for i, w := range s.waiters {
+				if w == waiter {
+					s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+					break
+				}
+			}
+		}
+		s.mutex.Unlock()
+		if granted {
+			// the slot has been handed over in the meantime
+			s.release()
+		}
+		return ctx.Err()
+	}
+}
+
+// release hands the slot over to the next waiting request or frees it.
+func (s *prioritySemaphore) release() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.waiters) > 0 {
+		waiter := s.waiters[0]
+		s.waiters = s.waiters[1:]
+		close(waiter.ready)
+		return
+	}
+	s.used--
+}
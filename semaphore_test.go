@@ -0,0 +1,51 @@
+package sdwan
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitForWaiters waits until a given number of requests is waiting for a slot.
+func waitForWaiters(s *prioritySemaphore, n int) {
+	for {
+		s.mutex.Lock()
+		waiting := len(s.waiters)
+		s.mutex.Unlock()
+		if waiting == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestPrioritySemaphore tests the order in which waiting requests acquire a slot.
+func TestPrioritySemaphore(t *testing.T) {
+	s := newPrioritySemaphore(1)
+	assert.NoError(t, s.acquire(context.Background(), 0))
+
+	order := make(chan int, 3)
+	for i, priority := range []int{0, 10, 5} {
+		go func(priority int) {
+			_ = s.acquire(context.Background(), priority)
+			order <- priority
+			s.release()
+		}(priority)
+		waitForWaiters(s, i+1)
+	}
+	s.release()
+	assert.Equal(t, 10, <-order)
+	assert.Equal(t, 5, <-order)
+	assert.Equal(t, 0, <-order)
+
+	// Canceled while waiting
+	assert.NoError(t, s.acquire(context.Background(), 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, s.acquire(ctx, 0))
+	waitForWaiters(s, 0)
+	s.release()
+	assert.NoError(t, s.acquire(context.Background(), 0))
+}
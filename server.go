@@ -0,0 +1,109 @@
+package sdwan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// ServerTime returns the current time of the vManage server as reported by the HTTP Date header.
+func (client *Client) ServerTime() (time.Time, error) {
+	req := client.NewReq("GET", "/dataservice/client/server", nil)
+	if err := client.signRequest(req.HttpReq); err != nil {
+		return time.Time{}, err
+	}
+	httpRes, err := client.HttpClient.Do(req.HttpReq)
+	if err != nil {
+		return time.Time{}, err
+	}
+	defer httpRes.Body.Close()
+	date := httpRes.Header.Get("Date")
+	if date == "" {
+		log.Printf("[ERROR] Server time retrieval failed: no Date header")
+		return time.Time{}, fmt.Errorf("server time retrieval failed, no Date header in response")
+	}
+	serverTime, err := http.ParseTime(date)
+	if err != nil {
+		log.Printf("[ERROR] Server time retrieval failed: invalid Date header %s", date)
+		return time.Time{}, fmt.Errorf("server time retrieval failed, invalid Date header: %w", err)
+	}
+	return serverTime, nil
+}
+
+// ServerVersion returns the software version of vManage, e.g. "20.9.1".
+func (client *Client) ServerVersion(mods ...func(*Req)) (string, error) {
+	res, err := client.Get("/client/server", mods...)
+	if err != nil {
+		return "", err
+	}
+	version := res.Get("data.platformVersion").String()
+	if version == "" {
+		return "", fmt.Errorf("%w: no platformVersion in /client/server", ErrUnexpectedSchema)
+	}
+	return version, nil
+}
+
+// ClockSkew returns the difference between the vManage server time and the local time.
+// A positive value means the server clock is ahead of the local clock.
+// The HTTP Date header has a resolution of one second, smaller differences can not be detected.
+func (client *Client) ClockSkew() (time.Duration, error) {
+	serverTime, err := client.ServerTime()
+	if err != nil {
+		return 0, err
+	}
+	return serverTime.Sub(client.clockNow()).Round(time.Second), nil
+}
+
+// WaitForReady waits until vManage is ready to serve API requests or the timeout expires.
+// After a restart, vManage might accept requests before all services (e.g. the configuration database) are up.
+// The readiness is polled using /client/server with an exponential backoff between attempts.
+func (client *Client) WaitForReady(ctx context.Context, timeout time.Duration) error {
+	deadline := client.clockNow().Add(timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	for attempts := 0; ; attempts++ {
+		res, err := client.Get("/client/server", Context(ctx))
+		if err == nil && res.Get("data").Exists() {
+			log.Printf("[DEBUG] vManage is ready")
+			return nil
+		}
+		if errors.Is(err, ErrInvalidCredentials) || errors.Is(err, ErrClientClosed) {
+			return err
+		}
+		log.Printf("[DEBUG] vManage not ready yet: %v, retries: %v", err, attempts)
+		client.clockSleepContext(ctx, backoffDelay(attempts, client.BackoffMinDelay, client.BackoffMaxDelay, client.BackoffDelayFactor))
+		err = ctx.Err()
+		if err == nil && !client.clockNow().Before(deadline) {
+			err = context.DeadlineExceeded
+		}
+		if err != nil {
+			log.Printf("[ERROR] vManage not ready after %v", timeout)
+			return fmt.Errorf("vManage not ready: %w", err)
+		}
+	}
+}
+
+// Ping checks the connectivity to vManage and the validity of the credentials without modifying anything.
+// The returned error indicates whether the authentication, the connection or the server failed.
+func (client *Client) Ping() error {
+	if err := client.Authenticate(); err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("ping failed, connection error: %w", err)
+		}
+		return fmt.Errorf("ping failed, authentication error: %w", err)
+	}
+	_, err := client.Get("/client/server", Retries(0))
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return fmt.Errorf("ping failed, connection error: %w", err)
+		}
+		return fmt.Errorf("ping failed, server error: %w", err)
+	}
+	return nil
+}
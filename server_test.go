@@ -0,0 +1,106 @@
+package sdwan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientClockSkew tests the Client::ServerTime and Client::ClockSkew methods.
+func TestClientClockSkew(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	withClock(&fakeClock{now: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)})(&client)
+
+	// Server clock one hour ahead
+	gock.New(testURL).
+		Get("/dataservice/client/server").
+		Reply(200).
+		SetHeader("Date", "Wed, 01 May 2024 13:00:00 GMT")
+	skew, err := client.ClockSkew()
+	assert.NoError(t, err)
+	assert.Equal(t, time.Hour, skew)
+
+	// Missing Date header
+	gock.New(testURL).Get("/dataservice/client/server").Reply(200)
+	_, err = client.ServerTime()
+	assert.Error(t, err)
+}
+
+// TestClientServerVersion tests the Client::ServerVersion method.
+func TestClientServerVersion(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/client/server").Reply(200).BodyString(`{"data":{"platformVersion":"20.9.1"}}`)
+	version, err := client.ServerVersion()
+	assert.NoError(t, err)
+	assert.Equal(t, "20.9.1", version)
+
+	gock.New(testURL).Get("/dataservice/client/server").Reply(200).BodyString(`{"data":{}}`)
+	_, err = client.ServerVersion()
+	assert.ErrorIs(t, err, ErrUnexpectedSchema)
+}
+
+// TestClientWaitForReady tests the Client::WaitForReady method.
+func TestClientWaitForReady(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	client.BackoffMinDelay = 0
+
+	// Ready after a failed attempt
+	gock.New(testURL).Get("/dataservice/client/server").Reply(200).BodyString(`{"error":{"code":"CONFIGDB_NOT_READY"}}`)
+	gock.New(testURL).Get("/dataservice/client/server").Reply(200).BodyString(`{"data":{"platformVersion":"20.9.1"}}`)
+	assert.NoError(t, client.WaitForReady(context.Background(), time.Minute))
+
+	// Timeout
+	gock.New(testURL).Get("/dataservice/client/server").Persist().Reply(503)
+	assert.ErrorIs(t, client.WaitForReady(context.Background(), 10*time.Millisecond), context.DeadlineExceeded)
+
+	// Timeout on the client clock
+	clock := &fakeClock{now: time.Now()}
+	withClock(clock)(&client)
+	client.BackoffMinDelay = 10
+	client.BackoffMaxDelay = 60
+	assert.ErrorIs(t, client.WaitForReady(context.Background(), 10*time.Minute), context.DeadlineExceeded)
+	var slept time.Duration
+	for _, d := range clock.slept {
+		assert.GreaterOrEqual(t, d, 10*time.Second)
+		slept += d
+	}
+	assert.GreaterOrEqual(t, slept, 10*time.Minute)
+
+	// Shut down client
+	client.Shutdown(context.Background())
+	assert.ErrorIs(t, client.WaitForReady(context.Background(), time.Minute), ErrClientClosed)
+}
+
+// TestClientPing tests the Client::Ping method.
+func TestClientPing(t *testing.T) {
+	defer gock.Off()
+	client := testClient()
+
+	// Authentication error
+	gock.New(testURL).Post("/j_security_check").Reply(200).BodyString(`<html><form action="j_security_check"></form></html>`)
+	err := client.Ping()
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	assert.ErrorContains(t, err, "authentication error")
+
+	client.Token = "ABC"
+
+	// Success
+	gock.New(testURL).Get("/dataservice/client/server").Reply(200).BodyString(`{"data":{}}`)
+	assert.NoError(t, client.Ping())
+
+	// Connection error
+	gock.New(testURL).Get("/dataservice/client/server").ReplyError(errors.New("fail"))
+	assert.ErrorContains(t, client.Ping(), "connection error")
+
+	// Server error
+	gock.New(testURL).Get("/dataservice/client/server").Reply(503)
+	assert.ErrorContains(t, client.Ping(), "server error")
+}
@@ -0,0 +1,35 @@
+package sdwan
+
+// settingPath returns the endpoint of a vManage administration setting.
+func settingPath(settingType string) string {
+	return "/settings/configuration/" + settingType
+}
+
+// GetSetting retrieves a vManage administration setting, e.g. "vbond", "organization" or "certificate".
+// The settings endpoints wrap the object in a "data" array, the returned result is the unwrapped object.
+// An empty object is returned if the setting has not been configured yet.
+func (client *Client) GetSetting(settingType string, mods ...func(*Req)) (Res, error) {
+	res, err := client.Get(settingPath(settingType), mods...)
+	if err != nil {
+		return res, err
+	}
+	setting := toRes(res.Get("data.0"))
+	if !setting.Exists() {
+		setting = newRes([]byte("{}"))
+	}
+	setting.CorrelationID = res.CorrelationID
+	return setting, nil
+}
+
+// UpdateSetting updates a vManage administration setting by retrieving it, deep merging the patch into it
+// and writing the object back using PUT, see Body.Merge. The settings endpoints return the object wrapped in
+// a "data" array but expect it unwrapped when writing.
+func (client *Client) UpdateSetting(settingType string, patch Body, mods ...func(*Req)) error {
+	setting, err := client.GetSetting(settingType, mods...)
+	if err != nil {
+		return err
+	}
+	body := Body{Str: setting.Raw}.Merge(patch)
+	_, err = client.Put(settingPath(settingType), body.Str, mods...)
+	return err
+}
@@ -0,0 +1,37 @@
+package sdwan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientGetSetting tests the Client::GetSetting method.
+func TestClientGetSetting(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/settings/configuration/organization").Reply(200).BodyString(`{"data":[{"org":"ACME"}]}`)
+	res, err := client.GetSetting("organization")
+	assert.NoError(t, err)
+	assert.Equal(t, "ACME", res.Get("org").String())
+
+	gock.New(testURL).Get("/dataservice/settings/configuration/vbond").Reply(200).BodyString(`{"data":[]}`)
+	res, err = client.GetSetting("vbond")
+	assert.NoError(t, err)
+	assert.Equal(t, "{}", res.Raw)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientUpdateSetting tests the Client::UpdateSetting method.
+func TestClientUpdateSetting(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/settings/configuration/vbond").Reply(200).BodyString(`{"data":[{"domainIp":"1.1.1.1","port":"12346"}]}`)
+	gock.New(testURL).Put("/dataservice/settings/configuration/vbond").BodyString(`{"domainIp":"2.2.2.2","port":"12346"}`).Reply(200)
+	err := client.UpdateSetting("vbond", Body{}.Set("domainIp", "2.2.2.2"))
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+}
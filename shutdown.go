@@ -0,0 +1,79 @@
+package sdwan
+
+import (
+	"context"
+	"log"
+	"sync"
+)
+
+// lifecycle tracks requests in flight to allow a graceful shutdown.
+type lifecycle struct {
+	mutex    sync.Mutex
+	closed   bool
+	inFlight sync.WaitGroup
+}
+
+// beginRequest registers a request in flight, ErrClientClosed is returned after Shutdown has been called.
+func (client *Client) beginRequest() error {
+	if client.lifecycle == nil {
+		return nil
+	}
+	client.lifecycle.mutex.Lock()
+	defer client.lifecycle.mutex.Unlock()
+	if client.lifecycle.closed {
+		return ErrClientClosed
+	}
+	client.lifecycle.inFlight.Add(1)
+	return nil
+}
+
+// endRequest unregisters a request registered with beginRequest.
+func (client *Client) endRequest() {
+	if client.lifecycle != nil {
+		client.lifecycle.inFlight.Done()
+	}
+}
+
+// isClosed checks whether Shutdown has been called.
+func (client *Client) isClosed() bool {
+	if client.lifecycle == nil {
+		return false
+	}
+	client.lifecycle.mutex.Lock()
+	defer client.lifecycle.mutex.Unlock()
+	return client.lifecycle.closed
+}
+
+// Shutdown gracefully shuts down the client. New requests are rejected with ErrClientClosed, requests in flight
+// (including their retries) are awaited until they complete or the context is done. Afterwards idle connections
+// are closed and the client is closed (see Close). The context error is returned if not all requests completed.
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+//	defer cancel()
+//	err := client.Shutdown(ctx)
+func (client *Client) Shutdown(ctx context.Context) error {
+	var err error
+	if client.lifecycle != nil {
+		client.lifecycle.mutex.Lock()
+		client.lifecycle.closed = true
+		client.lifecycle.mutex.Unlock()
+
+		drained := make(chan struct{})
+		go func() {
+			client.lifecycle.inFlight.Wait()
+			close(drained)
+		}()
+		select {
+		case <-drained:
+			log.Printf("[DEBUG] All requests completed")
+		case <-ctx.Done():
+			log.Printf("[WARNING] Shutdown before all requests completed: %v", ctx.Err())
+			err = ctx.Err()
+		}
+	}
+	client.HttpClient.CloseIdleConnections()
+	if closeErr := client.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
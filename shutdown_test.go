@@ -0,0 +1,38 @@
+package sdwan
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientShutdown tests the Client::Shutdown method.
+func TestClientShutdown(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/url").Reply(200)
+	_, err := client.Get("/url")
+	assert.NoError(t, err)
+
+	// Request in flight
+	assert.NoError(t, client.beginRequest())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err = client.Shutdown(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+
+	// New requests are rejected
+	_, err = client.Get("/url")
+	assert.True(t, errors.Is(err, ErrClientClosed))
+	_, err = client.Get("/url", NoAuth())
+	assert.True(t, errors.Is(err, ErrClientClosed))
+
+	// Request completed
+	client.endRequest()
+	assert.NoError(t, client.Shutdown(context.Background()))
+}
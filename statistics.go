@@ -0,0 +1,137 @@
+package sdwan
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/tidwall/gjson"
+)
+
+// StatisticsQuery builds a query for the vManage statistics API.
+// Usage example:
+//
+//	q := StatisticsQuery{}.
+//		Rule("entry_time", "last_n_hours", "date", "24").
+//		Rule("vdevice_name", "in", "string", "10.0.0.1").
+//		Fields("entry_time", "rx_kbps", "tx_kbps")
+type StatisticsQuery struct {
+	Str string
+}
+
+// Rule adds a rule to the query, e.g. Rule("entry_time", "last_n_hours", "date", "24").
+func (q StatisticsQuery) Rule(field, operator, valueType string, values ...string) StatisticsQuery {
+	rule := Body{}.
+		Set("field", field).
+		Set("operator", operator).
+		Set("type", valueType).
+		SetRaw("value", "[]")
+	for _, value := range values {
+		rule = rule.Set("value.-1", value)
+	}
+	q.Str = Body{Str: q.Str}.SetRaw("query.rules.-1", rule.Str).Str
+	return q
+}
+
+// Condition sets the condition used to combine the rules, either "AND" (default) or "OR".
+func (q StatisticsQuery) Condition(condition string) StatisticsQuery {
+	q.Str = Body{Str: q.Str}.Set("query.condition", condition).Str
+	return q
+}
+
+// Fields limits the returned fields.
+func (q StatisticsQuery) Fields(fields ...string) StatisticsQuery {
+	body := Body{Str: q.Str}.SetRaw("fields", "[]")
+	for _, field := range fields {
+		body = body.Set("fields.-1", field)
+	}
+	q.Str = body.Str
+	return q
+}
+
+// Aggregation sets the aggregation definition as raw JSON, e.g. `{"field":[{"property":"vdevice_name"}]}`.
+func (q StatisticsQuery) Aggregation(rawAggregation string) StatisticsQuery {
+	q.Str = Body{Str: q.Str}.SetRaw("aggregation", rawAggregation).Str
+	return q
+}
+
+// Size sets the number of entries returned per page.
+func (q StatisticsQuery) Size(size int) StatisticsQuery {
+	q.Str = Body{Str: q.Str}.SetRaw("size", strconv.Itoa(size)).Str
+	return q
+}
+
+// Body returns the query as request body.
+func (q StatisticsQuery) Body() Body {
+	body := Body{Str: q.Str}
+	if !body.Res().Get("query.condition").Exists() {
+		body = body.Set("query.condition", "AND")
+	}
+	if !body.Res().Get("query.rules").Exists() {
+		body = body.SetRaw("query.rules", "[]")
+	}
+	return body
+}
+
+// QueryStatistics queries a statistics type, e.g. "interface" or "approute", and returns all matching entries.
+// Pagination is handled transparently by following the scrollId of each page.
+func (client *Client) QueryStatistics(statType string, q StatisticsQuery, mods ...func(*Req)) ([]Res, error) {
+	return client.scrollQuery("/statistics/"+statType, q.Body().Str, mods...)
+}
+
+// scrollQuery posts a query and retrieves all pages of the result using the scrollId.
+// A missing or repeated scrollId aborts pagination instead of requesting the same page forever.
+func (client *Client) scrollQuery(path, query string, mods ...func(*Req)) ([]Res, error) {
+	res, err := client.Post(path, query, mods...)
+	if err != nil {
+		return nil, err
+	}
+	entries := toResArray(res.Get("data").Array())
+	previous := ""
+	for res.Get("pageInfo.hasMoreData").Bool() {
+		scrollID := res.Get("pageInfo.scrollId").String()
+		if scrollID == "" || scrollID == previous {
+			return entries, fmt.Errorf("%w: missing or repeated scrollId %q in %s", ErrUnexpectedSchema, scrollID, path)
+		}
+		previous = scrollID
+		res, err = client.Post(path+"/page?scrollId="+url.QueryEscape(scrollID), query, mods...)
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, toResArray(res.Get("data").Array())...)
+	}
+	return entries, nil
+}
+
+// AggregationBucket is a single bucket of a statistics aggregation response.
+type AggregationBucket struct {
+	// Time is the start of the time bucket (entry_time), zero if the aggregation is not grouped by time.
+	Time time.Time
+	// Labels are the grouping attributes of the bucket, e.g. "vdevice_name".
+	Labels map[string]string
+	// Values are the aggregated numeric values of the bucket, e.g. "rx_kbps" or "count".
+	Values map[string]float64
+}
+
+// ParseAggregation normalizes a statistics aggregation response into buckets.
+// Numeric attributes are returned as values, all other attributes as labels.
+func ParseAggregation(res Res) []AggregationBucket {
+	buckets := []AggregationBucket{}
+	for _, entry := range res.Get("data").Array() {
+		bucket := AggregationBucket{Labels: map[string]string{}, Values: map[string]float64{}}
+		entry.ForEach(func(key, value gjson.Result) bool {
+			switch {
+			case key.String() == "entry_time":
+				bucket.Time = time.UnixMilli(value.Int())
+			case value.Type == gjson.Number:
+				bucket.Values[key.String()] = value.Float()
+			default:
+				bucket.Labels[key.String()] = value.String()
+			}
+			return true
+		})
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
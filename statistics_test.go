@@ -0,0 +1,79 @@
+package sdwan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestStatisticsQuery tests the StatisticsQuery builder.
+func TestStatisticsQuery(t *testing.T) {
+	body := StatisticsQuery{}.
+		Rule("entry_time", "last_n_hours", "date", "24").
+		Fields("entry_time").
+		Size(100).
+		Body()
+	assert.Equal(t, `{"query":{"rules":[{"field":"entry_time","operator":"last_n_hours","type":"date","value":["24"]}],"condition":"AND"},"fields":["entry_time"],"size":100}`, body.Str)
+}
+
+// TestClientQueryStatistics tests the Client::QueryStatistics method.
+func TestClientQueryStatistics(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).
+		Post("/dataservice/statistics/interface").
+		Reply(200).
+		BodyString(`{"data":[{"id":1}],"pageInfo":{"scrollId":"S1","hasMoreData":true}}`)
+	gock.New(testURL).
+		Post("/dataservice/statistics/interface/page").
+		MatchParam("scrollId", "S1").
+		Reply(200).
+		BodyString(`{"data":[{"id":2}],"pageInfo":{"scrollId":"S1","hasMoreData":false}}`)
+	entries, err := client.QueryStatistics("interface", StatisticsQuery{})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, int64(2), entries[1].Get("id").Int())
+
+	// Repeated scrollId
+	gock.New(testURL).
+		Post("/dataservice/statistics/interface").
+		Reply(200).
+		BodyString(`{"data":[{"id":1}],"pageInfo":{"scrollId":"S1","hasMoreData":true}}`)
+	gock.New(testURL).
+		Post("/dataservice/statistics/interface/page").
+		MatchParam("scrollId", "S1").
+		Reply(200).
+		BodyString(`{"data":[{"id":2}],"pageInfo":{"scrollId":"S1","hasMoreData":true}}`)
+	entries, err = client.QueryStatistics("interface", StatisticsQuery{})
+	assert.ErrorIs(t, err, ErrUnexpectedSchema)
+	assert.Len(t, entries, 2)
+	assert.True(t, gock.IsDone())
+
+	// Missing scrollId
+	gock.New(testURL).
+		Post("/dataservice/statistics/interface").
+		Reply(200).
+		BodyString(`{"data":[{"id":1}],"pageInfo":{"hasMoreData":true}}`)
+	_, err = client.QueryStatistics("interface", StatisticsQuery{})
+	assert.ErrorIs(t, err, ErrUnexpectedSchema)
+	assert.True(t, gock.IsDone())
+}
+
+// TestParseAggregation tests the ParseAggregation function.
+func TestParseAggregation(t *testing.T) {
+	res := newRes([]byte(`{"data":[
+		{"entry_time":1700000000000,"vdevice_name":"10.0.0.1","rx_kbps":12.5,"count":3},
+		{"vdevice_name":"10.0.0.2","rx_kbps":7}
+	]}`))
+
+	buckets := ParseAggregation(res)
+	assert.Len(t, buckets, 2)
+	assert.Equal(t, time.UnixMilli(1700000000000), buckets[0].Time)
+	assert.Equal(t, map[string]string{"vdevice_name": "10.0.0.1"}, buckets[0].Labels)
+	assert.Equal(t, map[string]float64{"rx_kbps": 12.5, "count": 3}, buckets[0].Values)
+	assert.True(t, buckets[1].Time.IsZero())
+	assert.Empty(t, ParseAggregation(newRes([]byte(`{}`))))
+}
@@ -0,0 +1,102 @@
+package sdwan
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+)
+
+// GetStreamJSON makes a GET request and streams the response, calling onItem for each element of the "data" array.
+// The response is decoded incrementally, which avoids holding large result sets, e.g. of monitoring endpoints,
+// in memory. The "data" array may be nested within other objects. If onItem returns an error, reading the
+// response stops and the error is returned. Streamed requests are not retried.
+//
+//	err := client.GetStreamJSON("/device/interface", func(item sdwan.Res) error {
+//		println(item.Get("ifname").String())
+//		return nil
+//	})
+func (client *Client) GetStreamJSON(path string, onItem func(Res) error, mods ...func(*Req)) error {
+	if err := client.beginRequest(); err != nil {
+		return err
+	}
+	defer client.endRequest()
+	req := client.NewReq("GET", "/dataservice"+path, nil, mods...)
+	if !req.NoAuth {
+		if err := client.Authenticate(); err != nil {
+			return err
+		}
+		req.HttpReq.Header.Add("X-XSRF-TOKEN", client.Token)
+	}
+	if err := client.signRequest(req.HttpReq); err != nil {
+		return err
+	}
+	if err := client.acquireRequestSlot(req.HttpReq.Context(), req.Priority); err != nil {
+		return err
+	}
+	defer client.releaseRequestSlot()
+	log.Printf("[DEBUG] HTTP Request: %s, %s", req.HttpReq.Method, req.HttpReq.URL)
+	httpRes, err := client.HttpClient.Do(req.HttpReq)
+	if err != nil {
+		log.Printf("[ERROR] HTTP Connection failed: %s", err)
+		return err
+	}
+	defer httpRes.Body.Close()
+	if httpRes.StatusCode < 200 || httpRes.StatusCode > 299 {
+		log.Printf("[ERROR] HTTP Request failed: StatusCode %v", httpRes.StatusCode)
+		return statusError(httpRes.StatusCode)
+	}
+
+	found, err := streamDataArray(json.NewDecoder(httpRes.Body), "", onItem)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return fmt.Errorf("no data array in response")
+	}
+	return nil
+}
+
+// streamDataArray decodes the next JSON value, calling onItem for each element of the first "data" array found.
+// key is the object key of the value, it returns whether the "data" array has been found.
+func streamDataArray(dec *json.Decoder, key string, onItem func(Res) error) (bool, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return false, err
+	}
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return false, nil
+	}
+	switch {
+	case delim == '[' && key == "data":
+		for dec.More() {
+			var item json.RawMessage
+			if err := dec.Decode(&item); err != nil {
+				return true, err
+			}
+			if err := onItem(newRes(item)); err != nil {
+				return true, err
+			}
+		}
+		return true, nil
+	case delim == '{':
+		for dec.More() {
+			token, err := dec.Token()
+			if err != nil {
+				return false, err
+			}
+			if found, err := streamDataArray(dec, token.(string), onItem); found || err != nil {
+				return found, err
+			}
+		}
+	case delim == '[':
+		for dec.More() {
+			if found, err := streamDataArray(dec, "", onItem); found || err != nil {
+				return found, err
+			}
+		}
+	}
+	// consume the closing delimiter
+	_, err = dec.Token()
+	return false, err
+}
@@ -0,0 +1,57 @@
+package sdwan
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientGetStreamJSON tests the Client::GetStreamJSON method.
+func TestClientGetStreamJSON(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Top-level data array
+	gock.New(testURL).Get("/dataservice/url").Reply(200).BodyString(`{"header":{"columns":[{"a":1}]},"data":[{"name":"a"},{"name":"b"}]}`)
+	var names []string
+	err := client.GetStreamJSON("/url", func(item Res) error {
+		names = append(names, item.Get("name").String())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b"}, names)
+
+	// Nested data array
+	gock.New(testURL).Get("/dataservice/url").Reply(200).BodyString(`{"result":{"data":"x","items":[1],"data":[{"name":"c"}]}}`)
+	names = nil
+	err = client.GetStreamJSON("/url", func(item Res) error {
+		names = append(names, item.Get("name").String())
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"c"}, names)
+
+	// Stop early
+	stop := errors.New("stop")
+	gock.New(testURL).Get("/dataservice/url").Reply(200).BodyString(`{"data":[{"name":"a"},{"name":"b"}]}`)
+	count := 0
+	err = client.GetStreamJSON("/url", func(item Res) error {
+		count++
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, 1, count)
+
+	// Missing data array
+	gock.New(testURL).Get("/dataservice/url").Reply(200).BodyString(`{"items":[]}`)
+	err = client.GetStreamJSON("/url", func(item Res) error { return nil })
+	assert.Error(t, err)
+
+	// HTTP error
+	gock.New(testURL).Get("/dataservice/url").Reply(404)
+	err = client.GetStreamJSON("/url", func(item Res) error { return nil })
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.True(t, gock.IsDone())
+}
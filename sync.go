@@ -0,0 +1,90 @@
+package sdwan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// syncPollInterval is the delay between two configuration sync status checks.
+const syncPollInterval = 10 * time.Second
+
+// maxSyncWorkers is the maximum number of devices polled concurrently by WaitForDevicesSync.
+var maxSyncWorkers = 10
+
+// WaitForDeviceSync waits for the configuration of a WAN edge device to be in sync, see ErrSyncTimeout.
+func (client *Client) WaitForDeviceSync(deviceID string, timeout time.Duration, mods ...func(*Req)) error {
+	return client.waitForDeviceSync(deviceID, client.clockNow().Add(timeout), mods...)
+}
+
+// waitForDeviceSync polls the configuration status of a device until it is in sync or the deadline has passed.
+func (client *Client) waitForDeviceSync(deviceID string, deadline time.Time, mods ...func(*Req)) error {
+	query := "?uuid=" + url.QueryEscape(deviceID)
+	var status string
+	err := client.poll(context.Background(), deadline, syncPollInterval, func() (bool, error) {
+		res, err := client.Get("/system/device/vedges"+query, mods...)
+		if err != nil {
+			return false, err
+		}
+		edge := res.Get("data.0")
+		if !edge.Exists() {
+			return false, fmt.Errorf("device %s: %w", deviceID, ErrNotFound)
+		}
+		status = edge.Get("configStatusMessage").String()
+		if strings.EqualFold(status, "In Sync") {
+			log.Printf("[DEBUG] Device %s in sync", deviceID)
+			return true, nil
+		}
+		log.Printf("[DEBUG] Waiting for sync of device %s, status: %s", deviceID, status)
+		return false, nil
+	})
+	if errors.Is(err, errPollDeadline) {
+		log.Printf("[ERROR] Device %s not in sync, status: %s", deviceID, status)
+		return fmt.Errorf("%w: device %s, status: %s", ErrSyncTimeout, deviceID, status)
+	}
+	return err
+}
+
+// WaitForDevicesSync is like WaitForDeviceSync for multiple devices, it returns the outcome of each device.
+//
+//	results, err := client.WaitForDevicesSync([]string{"DEV1", "DEV2"}, 10*time.Minute)
+func (client *Client) WaitForDevicesSync(deviceIDs []string, timeout time.Duration, mods ...func(*Req)) (map[string]error, error) {
+	deadline := client.clockNow().Add(timeout)
+	results := make(map[string]error, len(deviceIDs))
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, maxSyncWorkers)
+	for _, deviceID := range deviceIDs {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(deviceID string) {
+			defer wg.Done()
+			err := client.waitForDeviceSync(deviceID, deadline, mods...)
+			<-workers
+			mutex.Lock()
+			results[deviceID] = err
+			mutex.Unlock()
+		}(deviceID)
+	}
+	wg.Wait()
+
+	var first error
+	failed := []string{}
+	for _, deviceID := range deviceIDs {
+		if err := results[deviceID]; err != nil {
+			if first == nil {
+				first = err
+			}
+			failed = append(failed, deviceID)
+		}
+	}
+	if first == nil {
+		return results, nil
+	}
+	return results, fmt.Errorf("%d of %d devices not in sync (%s): %w", len(failed), len(deviceIDs), strings.Join(failed, ", "), first)
+}
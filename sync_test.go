@@ -0,0 +1,56 @@
+package sdwan
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientWaitForDeviceSync tests the Client::WaitForDeviceSync method.
+func TestClientWaitForDeviceSync(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	withClock(&fakeClock{now: time.Now()})(&client)
+
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV1").Reply(200).
+		BodyString(`{"data":[{"configStatusMessage":"Sync Pending"}]}`)
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV1").Reply(200).
+		BodyString(`{"data":[{"configStatusMessage":"In Sync"}]}`)
+	err := client.WaitForDeviceSync("DEV1", time.Minute)
+	assert.NoError(t, err)
+
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV1").Reply(200).
+		BodyString(`{"data":[{"configStatusMessage":"Sync Pending"}]}`)
+	err = client.WaitForDeviceSync("DEV1", 0)
+	assert.True(t, errors.Is(err, ErrSyncTimeout))
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientWaitForDevicesSync tests the Client::WaitForDevicesSync method.
+func TestClientWaitForDevicesSync(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	withClock(&fakeClock{now: time.Now()})(&client)
+
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV1").Reply(200).
+		BodyString(`{"data":[{"configStatusMessage":"In Sync"}]}`)
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV2").Reply(200).
+		BodyString(`{"data":[{"configStatusMessage":"In Sync"}]}`)
+	results, err := client.WaitForDevicesSync([]string{"DEV1", "DEV2"}, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]error{"DEV1": nil, "DEV2": nil}, results)
+	assert.True(t, gock.IsDone())
+
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV1").Reply(200).
+		BodyString(`{"data":[{"configStatusMessage":"In Sync"}]}`)
+	gock.New(testURL).Get("/dataservice/system/device/vedges").MatchParam("uuid", "DEV2").Reply(200).
+		BodyString(`{"data":[]}`)
+	results, err = client.WaitForDevicesSync([]string{"DEV1", "DEV2"}, time.Minute)
+	assert.True(t, errors.Is(err, ErrNotFound))
+	assert.NoError(t, results["DEV1"])
+	assert.True(t, errors.Is(results["DEV2"], ErrNotFound))
+	assert.True(t, gock.IsDone())
+}
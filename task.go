@@ -0,0 +1,175 @@
+package sdwan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// taskPollInterval is the delay between two task status requests.
+const taskPollInterval = 5 * time.Second
+
+// errPollDeadline is returned by poll once the deadline has passed.
+var errPollDeadline = errors.New("poll deadline exceeded")
+
+// DeviceActivity is the result of a vManage task for a single device.
+type DeviceActivity struct {
+	// DeviceID is the ID of the device, e.g. its system IP.
+	DeviceID string
+	// Status is the status of the device, e.g. "Success" or "Failure".
+	Status string
+	// Messages are the activity messages logged for the device.
+	Messages []string
+}
+
+// TaskResult is the result of a vManage task.
+type TaskResult struct {
+	// Status is the overall status of the task, e.g. "in_progress" or "done".
+	Status string
+	// Devices are the results of the individual devices.
+	Devices []DeviceActivity
+	// Res is the complete task status response.
+	Res Res
+}
+
+// ParseTaskResult parses a task status response retrieved from /device/action/status/{taskId}.
+func ParseTaskResult(res Res) TaskResult {
+	result := TaskResult{
+		Status: res.Get("summary.status").String(),
+		Res:    res,
+	}
+	for _, device := range res.Get("data").Array() {
+		activity := DeviceActivity{
+			DeviceID: device.Get("deviceID").String(),
+			Status:   device.Get("status").String(),
+		}
+		if activity.DeviceID == "" {
+			activity.DeviceID = device.Get("uuid").String()
+		}
+		for _, message := range device.Get("activity").Array() {
+			activity.Messages = append(activity.Messages, message.String())
+		}
+		result.Devices = append(result.Devices, activity)
+	}
+	return result
+}
+
+// Succeeded checks whether the task is done and has not failed for any device.
+func (result TaskResult) Succeeded() bool {
+	return result.Status == "done" && result.Res.Get("summary.count.Failure").Int() == 0 && len(result.FailedDevices()) == 0
+}
+
+// FailedDevices returns the results of all devices the task failed for.
+func (result TaskResult) FailedDevices() []DeviceActivity {
+	var failed []DeviceActivity
+	for _, device := range result.Devices {
+		if strings.EqualFold(device.Status, "failure") {
+			failed = append(failed, device)
+		}
+	}
+	return failed
+}
+
+// device returns the result of the task for a device, identified by its UUID or system IP.
+func (result TaskResult) device(id, systemIP string) (DeviceActivity, bool) {
+	for i, device := range result.Devices {
+		if device.DeviceID == id || device.DeviceID == systemIP || result.Res.Get(fmt.Sprintf("data.%d.uuid", i)).String() == id {
+			return device, true
+		}
+	}
+	return DeviceActivity{}, false
+}
+
+// WaitForTask polls the status of an asynchronous vManage task until it is done or the timeout expires.
+// The final task result is returned, an error is returned if the task failed for at least one device.
+//
+//	res, _ := client.Post("/template/device/config/attachfeature", body.Str)
+//	result, err := client.WaitForTask(res.Get("id").String(), 10*time.Minute)
+//	for _, device := range result.FailedDevices() {
+//		println(device.DeviceID, strings.Join(device.Messages, "\n"))
+//	}
+func (client *Client) WaitForTask(taskID string, timeout time.Duration, mods ...func(*Req)) (TaskResult, error) {
+	return client.WaitForTaskContext(context.Background(), taskID, timeout, mods...)
+}
+
+// WaitForTaskContext is like WaitForTask, but stops waiting once the context is canceled.
+// If CancelTaskOnAbort is enabled, the vManage task is canceled as well, which avoids orphaned operations.
+func (client *Client) WaitForTaskContext(ctx context.Context, taskID string, timeout time.Duration, mods ...func(*Req)) (TaskResult, error) {
+	if taskID == "" {
+		return TaskResult{}, errors.New("empty task ID")
+	}
+	var result TaskResult
+	err := client.poll(ctx, client.clockNow().Add(timeout), taskPollInterval, func() (bool, error) {
+		res, err := client.Get("/device/action/status/"+taskID, append(mods, Context(ctx))...)
+		result = ParseTaskResult(res)
+		if err != nil {
+			return false, err
+		}
+		if result.Status != "done" {
+			log.Printf("[DEBUG] Waiting for task %s, status: %s", taskID, result.Status)
+			return false, nil
+		}
+		if !result.Succeeded() {
+			count := int(res.Get("summary.count.Failure").Int())
+			var failed []string
+			for _, device := range result.FailedDevices() {
+				failed = append(failed, device.DeviceID)
+			}
+			if len(failed) > count {
+				count = len(failed)
+			}
+			log.Printf("[ERROR] Task %s failed for %v devices: %s", taskID, count, strings.Join(failed, ", "))
+			return true, fmt.Errorf("task %s failed for %v devices: %s", taskID, count, strings.Join(failed, ", "))
+		}
+		log.Printf("[DEBUG] Task %s done", taskID)
+		return true, nil
+	})
+	if err != nil && ctx.Err() != nil {
+		return result, client.abortTask(ctx, taskID, mods...)
+	}
+	if errors.Is(err, errPollDeadline) {
+		log.Printf("[ERROR] Task %s not done after %v, status: %s", taskID, timeout, result.Status)
+		return result, fmt.Errorf("%w: task %s, status: %s", ErrTaskTimeout, taskID, result.Status)
+	}
+	return result, err
+}
+
+// poll invokes check until it reports completion or fails, waiting for the interval between two checks.
+// It returns errPollDeadline if the operation is not complete once the deadline has passed, and the context
+// error if the context is done while waiting. Deadline and waits are based on the client clock.
+func (client *Client) poll(ctx context.Context, deadline time.Time, interval time.Duration, check func() (done bool, err error)) error {
+	for {
+		done, err := check()
+		if done || err != nil {
+			return err
+		}
+		if !client.clockNow().Before(deadline) {
+			return errPollDeadline
+		}
+		client.clockSleepContext(ctx, interval)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// abortTask cancels a task if CancelTaskOnAbort is enabled and returns the context error.
+func (client *Client) abortTask(ctx context.Context, taskID string, mods ...func(*Req)) error {
+	log.Printf("[WARNING] Stopped waiting for task %s: %v", taskID, ctx.Err())
+	if client.CancelTaskOnAbort {
+		// the context of the caller is done, the cancellation is sent independently
+		if err := client.CancelTask(taskID, append(mods, Context(context.Background()))...); err != nil {
+			return fmt.Errorf("%w, cancel task %s: %v", ctx.Err(), taskID, err)
+		}
+	}
+	return ctx.Err()
+}
+
+// CancelTask cancels an in-progress vManage task, e.g. a software upgrade.
+func (client *Client) CancelTask(taskID string, mods ...func(*Req)) error {
+	_, err := client.Post("/device/action/status/cancel/"+taskID, "", mods...)
+	return err
+}
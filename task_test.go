@@ -0,0 +1,132 @@
+package sdwan
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientWaitForTask tests the Client::WaitForTask method.
+func TestClientWaitForTask(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	withClock(&fakeClock{now: time.Now()})(&client)
+
+	// Success
+	gock.New(testURL).Get("/dataservice/device/action/status/T1").Reply(200).BodyString(`{"summary":{"status":"in_progress"}}`)
+	gock.New(testURL).Get("/dataservice/device/action/status/T1").Reply(200).BodyString(`{"summary":{"status":"done","count":{"Success":1}}}`)
+	_, err := client.WaitForTask("T1", time.Minute)
+	assert.NoError(t, err)
+
+	// Failure
+	gock.New(testURL).Get("/dataservice/device/action/status/T1").Reply(200).
+		BodyString(`{"summary":{"status":"done","count":{"Failure":1}},"data":[{"deviceID":"1.1.1.1","status":"Failure"}]}`)
+	result, err := client.WaitForTask("T1", time.Minute)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "1.1.1.1")
+	assert.Len(t, result.FailedDevices(), 1)
+
+	// Timeout
+	gock.New(testURL).Get("/dataservice/device/action/status/T1").Reply(200).BodyString(`{"summary":{"status":"in_progress"}}`)
+	_, err = client.WaitForTask("T1", 0)
+	assert.ErrorIs(t, err, ErrTaskTimeout)
+
+	// Empty task ID
+	_, err = client.WaitForTask("", time.Minute)
+	assert.EqualError(t, err, "empty task ID")
+}
+
+// TestClientPoll tests the poll method using the client clock.
+func TestClientPoll(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	client := testClient()
+	withClock(clock)(&client)
+
+	checks := 0
+	err := client.poll(context.Background(), clock.now.Add(time.Minute), 10*time.Second, func() (bool, error) {
+		checks++
+		return checks == 3, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{10 * time.Second, 10 * time.Second}, clock.slept)
+
+	// Deadline
+	err = client.poll(context.Background(), clock.now.Add(time.Minute), 25*time.Second, func() (bool, error) {
+		return false, nil
+	})
+	assert.ErrorIs(t, err, errPollDeadline)
+	assert.Len(t, clock.slept, 5)
+}
+
+// TestParseTaskResult tests the ParseTaskResult function.
+func TestParseTaskResult(t *testing.T) {
+	res := newRes([]byte(`{
+		"summary":{"status":"done","count":{"Success":1,"Failure":1}},
+		"data":[
+			{"deviceID":"1.1.1.1","status":"Success","activity":["Attaching template","Done"]},
+			{"deviceID":"2.2.2.2","status":"Failure","activity":["Failed to update configuration"]}
+		]
+	}`))
+
+	result := ParseTaskResult(res)
+	assert.Equal(t, "done", result.Status)
+	assert.Len(t, result.Devices, 2)
+	assert.Equal(t, []string{"Attaching template", "Done"}, result.Devices[0].Messages)
+	assert.False(t, result.Succeeded())
+	assert.Equal(t, []DeviceActivity{{DeviceID: "2.2.2.2", Status: "Failure", Messages: []string{"Failed to update configuration"}}}, result.FailedDevices())
+}
+
+// TestClientWaitForTaskContext tests the Client::WaitForTaskContext method.
+func TestClientWaitForTaskContext(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Canceled while waiting
+	ctx, cancel := context.WithCancel(context.Background())
+	gock.New(testURL).Get("/dataservice/device/action/status/T1").Reply(200).BodyString(`{"summary":{"status":"in_progress"}}`)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := client.WaitForTaskContext(ctx, "T1", time.Minute)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	// Task canceled as well
+	client.CancelTaskOnAbort = true
+	gock.New(testURL).Post("/dataservice/device/action/status/cancel/T1").Reply(200)
+	_, err = client.WaitForTaskContext(ctx, "T1", time.Minute)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientWaitForTaskContextInFlight tests canceling WaitForTaskContext during a task status request.
+func TestClientWaitForTaskContextInFlight(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+	}))
+	defer server.Close()
+	client, _ := NewClient(server.URL, "usr", "pwd", true, BackoffMinDelay(10))
+	client.Token = "ABC"
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+	started := time.Now()
+	_, err := client.WaitForTaskContext(ctx, "T1", time.Minute)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Less(t, time.Since(started), time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+}
@@ -0,0 +1,81 @@
+package sdwan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// techSupportPollInterval is the delay between two tech support collection status checks.
+const techSupportPollInterval = 10 * time.Second
+
+// CollectTechSupport collects an admin-tech (tech support) bundle of a device, identified by its system IP,
+// and returns the path the bundle can be downloaded from, e.g. using client.Get(path, Accept("application/octet-stream")).
+// The collection status is polled until it completes or the timeout expires, onProgress (if not nil) is invoked
+// with the progress in percent after every poll. Core files are excluded from the bundle.
+//
+//	path, err := client.CollectTechSupport("10.0.0.1", func(percent int) { log.Printf("%d%%", percent) }, 30*time.Minute)
+func (client *Client) CollectTechSupport(deviceID string, onProgress func(percent int), timeout time.Duration, mods ...func(*Req)) (string, error) {
+	deadline := client.clockNow().Add(timeout)
+	body := Body{}.
+		Set("deviceIP", deviceID).
+		SetRaw("exclude_cores", "true").
+		SetRaw("exclude_tech", "false").
+		SetRaw("exclude_logs", "false")
+	res, err := client.Post("/device/tools/admintech", body.Str, mods...)
+	if err != nil {
+		return "", err
+	}
+	token := res.Get("data.0.requestTokenId").String()
+	if token == "" {
+		token = res.Get("requestTokenId").String()
+	}
+	if token == "" {
+		return "", fmt.Errorf("tech support request of device %s: %w", deviceID, ErrNotFound)
+	}
+
+	var path string
+	percent := 0
+	err = client.poll(context.Background(), deadline, techSupportPollInterval, func() (bool, error) {
+		res, err := client.Get("/device/tools/admintechs", mods...)
+		if err != nil {
+			return false, err
+		}
+		entry := res.Get(fmt.Sprintf(`data.#(requestTokenId==%q)`, token))
+		if !entry.Exists() {
+			return false, fmt.Errorf("tech support request %s of device %s: %w", token, deviceID, ErrNotFound)
+		}
+		state := strings.ToLower(entry.Get("state").String())
+		percent = int(entry.Get("progress").Int())
+		if state == "done" || state == "success" {
+			percent = 100
+		}
+		if onProgress != nil {
+			onProgress(percent)
+		}
+		switch state {
+		case "done", "success":
+			fileName := entry.Get("fileName").String()
+			if fileName == "" {
+				fileName = entry.Get("filename").String()
+			}
+			log.Printf("[DEBUG] Tech support of device %s collected: %s", deviceID, fileName)
+			path = "/device/tools/admintech/download/" + url.PathEscape(fileName)
+			return true, nil
+		case "failed", "failure", "error":
+			log.Printf("[ERROR] Tech support collection of device %s failed: %s", deviceID, entry.Raw)
+			return false, fmt.Errorf("tech support collection of device %s failed: %s", deviceID, entry.Get("message").String())
+		}
+		log.Printf("[DEBUG] Waiting for tech support of device %s, progress: %v%%", deviceID, percent)
+		return false, nil
+	})
+	if errors.Is(err, errPollDeadline) {
+		log.Printf("[ERROR] Tech support of device %s not collected after %v, progress: %v%%", deviceID, timeout, percent)
+		return "", fmt.Errorf("%w: device %s, progress: %v%%", ErrTechSupportTimeout, deviceID, percent)
+	}
+	return path, err
+}
@@ -0,0 +1,37 @@
+package sdwan
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientCollectTechSupport tests the Client::CollectTechSupport method.
+func TestClientCollectTechSupport(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	withClock(&fakeClock{now: time.Now()})(&client)
+
+	gock.New(testURL).Post("/dataservice/device/tools/admintech").
+		BodyString(`{"deviceIP":"10.0.0.1","exclude_cores":true,"exclude_tech":false,"exclude_logs":false}`).
+		Reply(200).BodyString(`{"data":[{"requestTokenId":"R1"}]}`)
+	gock.New(testURL).Get("/dataservice/device/tools/admintechs").Reply(200).
+		BodyString(`{"data":[{"requestTokenId":"R0","state":"done"},{"requestTokenId":"R1","state":"inprogress","progress":40}]}`)
+	gock.New(testURL).Get("/dataservice/device/tools/admintechs").Reply(200).
+		BodyString(`{"data":[{"requestTokenId":"R1","state":"done","fileName":"10.0.0.1-admin-tech.tar.gz"}]}`)
+	var progress []int
+	path, err := client.CollectTechSupport("10.0.0.1", func(percent int) { progress = append(progress, percent) }, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "/device/tools/admintech/download/10.0.0.1-admin-tech.tar.gz", path)
+	assert.Equal(t, []int{40, 100}, progress)
+
+	gock.New(testURL).Post("/dataservice/device/tools/admintech").Reply(200).BodyString(`{"data":[{"requestTokenId":"R2"}]}`)
+	gock.New(testURL).Get("/dataservice/device/tools/admintechs").Reply(200).
+		BodyString(`{"data":[{"requestTokenId":"R2","state":"inprogress"}]}`)
+	_, err = client.CollectTechSupport("10.0.0.1", nil, 0)
+	assert.True(t, errors.Is(err, ErrTechSupportTimeout))
+	assert.True(t, gock.IsDone())
+}
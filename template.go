@@ -0,0 +1,188 @@
+package sdwan
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/tidwall/gjson"
+)
+
+// templateCache caches the template name to ID mappings of feature and device templates.
+type templateCache struct {
+	mutex sync.Mutex
+	ids   map[string]map[string]string
+}
+
+func newTemplateCache() *templateCache {
+	return &templateCache{ids: map[string]map[string]string{}}
+}
+
+func (cache *templateCache) get(kind, name string) (string, bool) {
+	if cache == nil {
+		return "", false
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	id, ok := cache.ids[kind][name]
+	return id, ok
+}
+
+func (cache *templateCache) set(kind string, ids map[string]string) {
+	if cache == nil {
+		return
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.ids[kind] = ids
+}
+
+func (cache *templateCache) invalidate() {
+	if cache == nil {
+		return
+	}
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.ids = map[string]map[string]string{}
+}
+
+// GetTemplateInputs retrieves the device specific variables required to attach a device template.
+// The result contains the variable definitions in "header.columns" and one entry per device in "data".
+// Use TemplateInputValues to map variable names to their values.
+func (client *Client) GetTemplateInputs(templateID string, deviceIDs []string, mods ...func(*Req)) (Res, error) {
+	body := Body{}.
+		Set("templateId", templateID).
+		SetRaw("deviceIds", "[]").
+		SetRaw("isEdited", "false").
+		SetRaw("isMasterEdited", "false")
+	for _, id := range deviceIDs {
+		body = body.Set("deviceIds.-1", id)
+	}
+	return client.Post("/template/device/config/input", body.Str, mods...)
+}
+
+// TemplateInputValues parses the result of GetTemplateInputs and returns one map per device,
+// mapping each variable name (the "property" of a column) to its current value.
+func TemplateInputValues(res Res) []map[string]string {
+	columns := res.Get("header.columns").Array()
+	values := []map[string]string{}
+	res.Get("data").ForEach(func(_, device gjson.Result) bool {
+		vars := map[string]string{}
+		for _, column := range columns {
+			property := column.Get("property").String()
+			vars[property] = device.Get(gjson.Escape(property)).String()
+		}
+		values = append(values, vars)
+		return true
+	})
+	return values
+}
+
+// GetFeatureTemplateByName retrieves a feature template by its name.
+// ErrNotFound is returned if no feature template with this name exists.
+// The name to ID mapping is cached for the lifetime of the client, see InvalidateTemplateCache.
+func (client *Client) GetFeatureTemplateByName(name string, mods ...func(*Req)) (Res, error) {
+	return client.getTemplateByName("feature", name, mods...)
+}
+
+// GetDeviceTemplateByName retrieves a device template by its name.
+// ErrNotFound is returned if no device template with this name exists.
+// The name to ID mapping is cached for the lifetime of the client, see InvalidateTemplateCache.
+func (client *Client) GetDeviceTemplateByName(name string, mods ...func(*Req)) (Res, error) {
+	return client.getTemplateByName("device", name, mods...)
+}
+
+// InvalidateTemplateCache clears the cached template name to ID mappings.
+func (client *Client) InvalidateTemplateCache() {
+	client.templateCache.invalidate()
+}
+
+// getTemplateByName looks up the ID of a template by name and retrieves the template.
+// A cached ID is only used if the template can still be retrieved, otherwise the cache is refreshed.
+func (client *Client) getTemplateByName(kind, name string, mods ...func(*Req)) (Res, error) {
+	if id, ok := client.templateCache.get(kind, name); ok {
+		res, err := client.Get("/template/"+kind+"/object/"+id, mods...)
+		if err == nil {
+			return res, nil
+		}
+	}
+	list, err := client.Get("/template/"+kind, mods...)
+	if err != nil {
+		return Res{}, err
+	}
+	ids := map[string]string{}
+	for _, template := range list.Get("data").Array() {
+		ids[template.Get("templateName").String()] = template.Get("templateId").String()
+	}
+	client.templateCache.set(kind, ids)
+	id, ok := ids[name]
+	if !ok {
+		return Res{}, fmt.Errorf("%s template %s: %w", kind, name, ErrNotFound)
+	}
+	return client.Get("/template/"+kind+"/object/"+id, mods...)
+}
+
+// DeviceVars are the variable values of a device used to attach a device template, e.g. as returned by
+// TemplateInputValues including the "csv-deviceId" attribute.
+type DeviceVars map[string]string
+
+// DeviceValidation is the template validation result of a single device.
+type DeviceValidation struct {
+	// DeviceID is the UUID of the device ("csv-deviceId").
+	DeviceID string
+	// Valid indicates whether the configuration of the device is valid.
+	Valid bool
+	// Messages are the validation error messages.
+	Messages []string
+}
+
+// ValidationResult is the result of ValidateDeviceTemplate.
+type ValidationResult struct {
+	// Valid indicates whether the configuration of all devices is valid.
+	Valid bool
+	// Devices are the validation results of the individual devices.
+	Devices []DeviceValidation
+}
+
+// ValidateDeviceTemplate validates the configuration generated from a device template for the given devices
+// without attaching it. Validation errors reported by vManage are part of the result, an error is only returned
+// if the validation itself fails.
+//
+//	inputs, _ := client.GetTemplateInputs(templateID, deviceIDs)
+//	var vars []sdwan.DeviceVars
+//	for _, values := range sdwan.TemplateInputValues(inputs) {
+//		vars = append(vars, values)
+//	}
+//	result, err := client.ValidateDeviceTemplate(templateID, vars)
+func (client *Client) ValidateDeviceTemplate(templateID string, deviceVars []DeviceVars, mods ...func(*Req)) (ValidationResult, error) {
+	result := ValidationResult{Valid: true}
+	for _, vars := range deviceVars {
+		body := Body{}.
+			Set("templateId", templateID).
+			SetRaw("device", "{}").
+			SetRaw("isEdited", "false").
+			SetRaw("isMasterEdited", "false")
+		for name, value := range vars {
+			body = body.Set("device."+gjson.Escape(name), value)
+		}
+		validation := DeviceValidation{DeviceID: vars["csv-deviceId"], Valid: true}
+		res, err := client.Post("/template/device/config/verify", body.Str, mods...)
+		if res.Get("error").Exists() {
+			validation.Valid = false
+			validation.Messages = append(validation.Messages, res.Get("error.message").String())
+			for _, detail := range res.ErrorDetails() {
+				validation.Messages = append(validation.Messages, detail.String())
+			}
+		} else if err != nil {
+			return result, err
+		}
+		if res.Get("data.0.validity").String() == "invalid" {
+			validation.Valid = false
+			validation.Messages = append(validation.Messages, res.Get("data.0.message").String())
+		}
+		if !validation.Valid {
+			result.Valid = false
+		}
+		result.Devices = append(result.Devices, validation)
+	}
+	return result, nil
+}
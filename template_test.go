@@ -0,0 +1,81 @@
+package sdwan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientGetTemplateInputs tests the Client::GetTemplateInputs method.
+func TestClientGetTemplateInputs(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).
+		Post("/dataservice/template/device/config/input").
+		BodyString(`{"templateId":"T1","deviceIds":["D1"],"isEdited":false,"isMasterEdited":false}`).
+		Reply(200).
+		BodyString(`{"header":{"columns":[{"property":"csv-deviceId","title":"Device ID"},{"property":"//system/host-name","title":"Hostname"}]},"data":[{"csv-deviceId":"D1","//system/host-name":"edge1"}]}`)
+	res, err := client.GetTemplateInputs("T1", []string{"D1"})
+	assert.NoError(t, err)
+
+	values := TemplateInputValues(res)
+	assert.Len(t, values, 1)
+	assert.Equal(t, "D1", values[0]["csv-deviceId"])
+	assert.Equal(t, "edge1", values[0]["//system/host-name"])
+}
+
+// TestClientGetFeatureTemplateByName tests the Client::GetFeatureTemplateByName method.
+func TestClientGetFeatureTemplateByName(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// Lookup populates the cache
+	gock.New(testURL).Get("/dataservice/template/feature").Reply(200).BodyString(`{"data":[{"templateId":"F1","templateName":"system"}]}`)
+	gock.New(testURL).Get("/dataservice/template/feature/object/F1").Reply(200).BodyString(`{"templateName":"system"}`)
+	res, err := client.GetFeatureTemplateByName("system")
+	assert.NoError(t, err)
+	assert.Equal(t, "system", res.Get("templateName").String())
+
+	// Cached lookup
+	gock.New(testURL).Get("/dataservice/template/feature/object/F1").Reply(200).BodyString(`{"templateName":"system"}`)
+	_, err = client.GetFeatureTemplateByName("system")
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	// Not found
+	gock.New(testURL).Get("/dataservice/template/feature").Reply(200).BodyString(`{"data":[]}`)
+	_, err = client.GetFeatureTemplateByName("other")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+// TestClientValidateDeviceTemplate tests the Client::ValidateDeviceTemplate method.
+func TestClientValidateDeviceTemplate(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).
+		Post("/dataservice/template/device/config/verify").
+		BodyString(`{"templateId":"T1","device":{"csv-deviceId":"D1"},"isEdited":false,"isMasterEdited":false}`).
+		Reply(200).
+		BodyString(`{"data":[{"validity":"valid"}]}`)
+	gock.New(testURL).
+		Post("/dataservice/template/device/config/verify").
+		BodyString(`{"templateId":"T1","device":{"csv-deviceId":"D2"},"isEdited":false,"isMasterEdited":false}`).
+		Reply(400).
+		BodyString(`{"error":{"message":"Failed to validate configuration","details":[{"code":"VAR","message":"Missing variable"}]}}`)
+	result, err := client.ValidateDeviceTemplate("T1", []DeviceVars{{"csv-deviceId": "D1"}, {"csv-deviceId": "D2"}})
+	assert.NoError(t, err)
+	assert.False(t, result.Valid)
+	assert.Equal(t, []DeviceValidation{
+		{DeviceID: "D1", Valid: true},
+		{DeviceID: "D2", Valid: false, Messages: []string{"Failed to validate configuration", "VAR: Missing variable"}},
+	}, result.Devices)
+
+	// Request failure
+	gock.New(testURL).Post("/dataservice/template/device/config/verify").Reply(500)
+	_, err = client.ValidateDeviceTemplate("T1", []DeviceVars{{"csv-deviceId": "D1"}})
+	assert.Error(t, err)
+	assert.True(t, gock.IsDone())
+}
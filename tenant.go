@@ -0,0 +1,142 @@
+package sdwan
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// TenantSessionHeader is the header used to scope requests of a provider to a tenant, see OnBehalfOfTenant.
+const TenantSessionHeader = "VSessionId"
+
+// OnBehalfOfTenant makes a request of a provider (multi-tenant vManage) on behalf of a tenant.
+// The client retrieves a tenant session ID once per tenant and login (POST /tenant/{tenantId}/vsessionid)
+// and sends it in the VSessionId header. This avoids separate logins for every tenant.
+//
+// The header is honored by tenant-scoped endpoints, e.g. /device, /template, /template/policy and the
+// monitoring and statistics endpoints, the same way as in the provider-as-tenant view of the GUI.
+// Provider-level endpoints such as /tenant, /admin, /settings and /clusterManagement ignore it.
+//
+//	res, err := client.Get("/device", OnBehalfOfTenant("a3b9c7d2-..."))
+func OnBehalfOfTenant(tenantID string) func(*Req) {
+	return func(req *Req) {
+		req.Tenant = tenantID
+	}
+}
+
+// tenantSessions caches the tenant session IDs of a provider session.
+type tenantSessions struct {
+	mutex sync.Mutex
+	// token is the token of the session the IDs belong to
+	token string
+	ids   map[string]string
+}
+
+// tenantSessionID returns the session ID for requests on behalf of a tenant, retrieving it if necessary.
+// Cached session IDs are discarded once the provider session changes.
+func (client *Client) tenantSessionID(tenantID string) (string, error) {
+	cache := client.tenantSessions
+	client.AuthenticationMutex.Lock()
+	token := client.Token
+	client.AuthenticationMutex.Unlock()
+	if cache != nil {
+		cache.mutex.Lock()
+		id, ok := cache.ids[tenantID]
+		valid := ok && cache.token == token
+		cache.mutex.Unlock()
+		if valid {
+			return id, nil
+		}
+	}
+	res, err := client.Post("/tenant/"+url.PathEscape(tenantID)+"/vsessionid", "{}", NoLogPayload)
+	if err != nil {
+		return "", fmt.Errorf("session of tenant %s: %w", tenantID, err)
+	}
+	id := res.Get("VSessionId").String()
+	if id == "" {
+		return "", fmt.Errorf("session of tenant %s: %w", tenantID, ErrNotFound)
+	}
+	if cache == nil {
+		return id, nil
+	}
+	cache.mutex.Lock()
+	if cache.token != token {
+		cache.token = token
+		cache.ids = map[string]string{}
+	}
+	cache.ids[tenantID] = id
+	cache.mutex.Unlock()
+	return id, nil
+}
+
+// TenantSpec describes a tenant of a multi-tenant (provider mode) vManage.
+type TenantSpec struct {
+	// Name is the tenant name.
+	Name string
+	// Description is the tenant description.
+	Description string
+	// OrgName is the organization name of the tenant, e.g. "provider-org-tenant1".
+	OrgName string
+	// SubDomain is the tenant specific vManage sub-domain, e.g. "tenant1.vmanage.example.com".
+	SubDomain string
+	// WanEdgeForecast is the expected number of WAN edge devices of the tenant.
+	WanEdgeForecast int
+	// VSmarts are the UUIDs of the vSmart controllers the tenant is assigned to.
+	// If empty, vManage assigns the tenant to vSmart controllers.
+	VSmarts []string
+}
+
+// Body creates the tenant request body.
+func (spec TenantSpec) Body() Body {
+	body := Body{}.
+		Set("name", spec.Name).
+		Set("desc", spec.Description).
+		Set("orgName", spec.OrgName).
+		Set("subDomain", spec.SubDomain).
+		SetRaw("wanEdgeForecast", strconv.Itoa(spec.WanEdgeForecast))
+	if len(spec.VSmarts) > 0 {
+		body = body.SetRaw("vSmarts", "[]")
+		for _, vSmart := range spec.VSmarts {
+			body = body.Set("vSmarts.-1", vSmart)
+		}
+	}
+	return body
+}
+
+// CreateTenant creates a tenant, waits for the onboarding task to complete and returns the ID of the new tenant.
+func (client *Client) CreateTenant(spec TenantSpec, timeout time.Duration, mods ...func(*Req)) (string, error) {
+	res, err := client.Post("/tenant/async", spec.Body().Str, mods...)
+	if err != nil {
+		return "", err
+	}
+	if _, err := client.WaitForTask(res.Get("id").String(), timeout, mods...); err != nil {
+		return "", err
+	}
+	tenants, err := client.Get("/tenant", mods...)
+	if err != nil {
+		return "", err
+	}
+	for _, tenant := range tenants.Get("data").Array() {
+		if tenant.Get("name").String() == spec.Name {
+			return tenant.Get("tenantId").String(), nil
+		}
+	}
+	return "", fmt.Errorf("tenant %s: %w", spec.Name, ErrNotFound)
+}
+
+// DeleteTenant deletes a tenant and waits for the deletion task to complete.
+// vManage requires the password of the provider user to confirm the deletion.
+func (client *Client) DeleteTenant(tenantID string, timeout time.Duration, mods ...func(*Req)) error {
+	body := Body{}.
+		Set("password", client.Pwd).
+		SetRaw("tenantIdList", "[]").
+		Set("tenantIdList.-1", tenantID)
+	res, err := client.DeleteBody("/tenant/bulk/async", body.Str, append([]func(*Req){NoLogPayload}, mods...)...)
+	if err != nil {
+		return err
+	}
+	_, err = client.WaitForTask(res.Get("id").String(), timeout, mods...)
+	return err
+}
@@ -0,0 +1,67 @@
+package sdwan
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientCreateTenant tests the Client::CreateTenant method.
+func TestClientCreateTenant(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).
+		Post("/dataservice/tenant/async").
+		BodyString(`{"name":"t1","desc":"Tenant 1","orgName":"org-t1","subDomain":"t1.example.com","wanEdgeForecast":10}`).
+		Reply(200).
+		BodyString(`{"id":"T1"}`)
+	gock.New(testURL).Get("/dataservice/device/action/status/T1").Reply(200).BodyString(`{"summary":{"status":"done"}}`)
+	gock.New(testURL).Get("/dataservice/tenant").Reply(200).BodyString(`{"data":[{"name":"t1","tenantId":"ID1"}]}`)
+	id, err := client.CreateTenant(TenantSpec{Name: "t1", Description: "Tenant 1", OrgName: "org-t1", SubDomain: "t1.example.com", WanEdgeForecast: 10}, time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, "ID1", id)
+}
+
+// TestClientDeleteTenant tests the Client::DeleteTenant method.
+func TestClientDeleteTenant(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).
+		Delete("/dataservice/tenant/bulk/async").
+		BodyString(`{"password":"pwd","tenantIdList":["ID1"]}`).
+		Reply(200).
+		BodyString(`{"id":"T1"}`)
+	gock.New(testURL).Get("/dataservice/device/action/status/T1").Reply(200).BodyString(`{"summary":{"status":"done"}}`)
+	assert.NoError(t, client.DeleteTenant("ID1", time.Minute))
+}
+
+// TestClientOnBehalfOfTenant tests the OnBehalfOfTenant modifier.
+func TestClientOnBehalfOfTenant(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	// session ID retrieved once per tenant
+	gock.New(testURL).Post("/dataservice/tenant/ID1/vsessionid").Reply(200).BodyString(`{"VSessionId":"S1"}`)
+	gock.New(testURL).Get("/dataservice/device").MatchHeader("VSessionId", "S1").Times(2).Reply(200)
+	_, err := client.Get("/device", OnBehalfOfTenant("ID1"))
+	assert.NoError(t, err)
+	_, err = client.Get("/device", OnBehalfOfTenant("ID1"))
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	// new session ID after the provider session changed
+	client.Token = "DEF"
+	gock.New(testURL).Post("/dataservice/tenant/ID1/vsessionid").MatchHeader("X-XSRF-TOKEN", "DEF").Reply(200).BodyString(`{"VSessionId":"S2"}`)
+	gock.New(testURL).Get("/dataservice/device").MatchHeader("VSessionId", "S2").Reply(200)
+	_, err = client.Get("/device", OnBehalfOfTenant("ID1"))
+	assert.NoError(t, err)
+	assert.True(t, gock.IsDone())
+
+	gock.New(testURL).Post("/dataservice/tenant/ID2/vsessionid").Reply(200).BodyString(`{}`)
+	_, err = client.Get("/device", OnBehalfOfTenant("ID2"))
+	assert.Error(t, err)
+}
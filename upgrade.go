@@ -0,0 +1,226 @@
+package sdwan
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// UpgradeResult is the result of a software upgrade of multiple devices.
+type UpgradeResult struct {
+	// Devices maps the IDs (UUIDs) of the devices to the error of their upgrade, nil if successful.
+	Devices map[string]error
+	// Tasks are the results of the install and activate tasks in the order they have been run.
+	Tasks []TaskResult
+}
+
+// FailedDevices returns the sorted IDs of all devices the upgrade failed for.
+func (result UpgradeResult) FailedDevices() []string {
+	failed := []string{}
+	for id, err := range result.Devices {
+		if err != nil {
+			failed = append(failed, id)
+		}
+	}
+	sort.Strings(failed)
+	return failed
+}
+
+// SoftwareImage is a software image of the vManage software repository.
+type SoftwareImage struct {
+	// Version is the software version of the image, e.g. "17.09.04a".
+	Version string
+	// PlatformFamilies are the platform families the image applies to, e.g. "c8000v" or "vedge".
+	PlatformFamilies []string
+	// Available indicates whether the image file has been uploaded and can be installed.
+	Available bool
+	// FileName is the file name of the image.
+	FileName string
+	// Res is the complete image object.
+	Res Res
+}
+
+// ParseSoftwareImage parses a software image retrieved from /device/action/software/images. Depending on the
+// vManage release the platform families are returned as an array or a comma separated string, and the
+// file name as "availableFiles" or "fileName".
+func ParseSoftwareImage(res Res) SoftwareImage {
+	image := SoftwareImage{
+		Version:  res.Get("versionName").String(),
+		FileName: res.Get("availableFiles").String(),
+		Res:      res,
+	}
+	if image.Version == "" {
+		image.Version = res.Get("version").String()
+	}
+	if image.FileName == "" {
+		image.FileName = res.Get("fileName").String()
+	}
+	platformFamily := res.Get("platformFamily")
+	if platformFamily.IsArray() {
+		for _, family := range platformFamily.Array() {
+			image.PlatformFamilies = append(image.PlatformFamilies, family.String())
+		}
+	} else {
+		for _, family := range strings.Split(platformFamily.String(), ",") {
+			if family = strings.TrimSpace(family); family != "" {
+				image.PlatformFamilies = append(image.PlatformFamilies, family)
+			}
+		}
+	}
+	status := res.Get("status").String()
+	image.Available = image.FileName != "" && (status == "" || strings.EqualFold(status, "available"))
+	return image
+}
+
+// ListSoftwareImages retrieves the software images of the vManage software repository, e.g. to resolve a version
+// to an uploaded image before calling UpgradeDevices.
+func (client *Client) ListSoftwareImages(mods ...func(*Req)) ([]SoftwareImage, error) {
+	data, err := client.GetData("/device/action/software/images", mods...)
+	if err != nil {
+		return nil, err
+	}
+	images := []SoftwareImage{}
+	for _, image := range data {
+		images = append(images, ParseSoftwareImage(image))
+	}
+	return images, nil
+}
+
+// upgradeDevice is a device to be upgraded, see UpgradeDevices.
+type upgradeDevice struct {
+	id       string
+	systemIP string
+}
+
+// upgradeDeviceTypes maps device types to the device types of software actions.
+var upgradeDeviceTypes = map[string]string{
+	"vedge":   "vedge",
+	"vsmart":  "controller",
+	"vbond":   "controller",
+	"vmanage": "vmanage",
+}
+
+// UpgradeDevices installs a software image on multiple devices, optionally activates it, and waits for the
+// resulting tasks to complete. Devices of different types (WAN edges, controllers) are upgraded by separate
+// tasks. The image is only activated on devices it has been installed on successfully. The timeout applies
+// to all tasks together.
+//
+// The result contains the outcome of every device. If the upgrade fails for any device, an error listing
+// the failed devices is returned as well.
+//
+//	result, err := client.UpgradeDevices([]string{"C8K-1234"}, "17.09.04a", true, time.Hour)
+func (client *Client) UpgradeDevices(deviceIDs []string, imageVersion string, activate bool, timeout time.Duration, mods ...func(*Req)) (UpgradeResult, error) {
+	deadline := client.clockNow().Add(timeout)
+	result := UpgradeResult{Devices: make(map[string]error, len(deviceIDs))}
+
+	inventory, err := client.Get("/device", mods...)
+	if err != nil {
+		return result, err
+	}
+	groups := map[string][]upgradeDevice{}
+	for _, id := range deviceIDs {
+		device := inventory.Get(fmt.Sprintf(`data.#(uuid==%q)`, id))
+		deviceType, ok := upgradeDeviceTypes[device.Get("device-type").String()]
+		if !device.Exists() || !ok {
+			result.Devices[id] = fmt.Errorf("device %s: %w", id, ErrNotFound)
+			continue
+		}
+		groups[deviceType] = append(groups[deviceType], upgradeDevice{id: id, systemIP: device.Get("system-ip").String()})
+	}
+
+	deviceTypes := make([]string, 0, len(groups))
+	for deviceType := range groups {
+		deviceTypes = append(deviceTypes, deviceType)
+	}
+	sort.Strings(deviceTypes)
+	for _, deviceType := range deviceTypes {
+		devices := groups[deviceType]
+		body := Body{}.
+			Set("action", "install").
+			SetRaw("input", Body{}.
+				SetRaw("vEdgeVPN", "0").
+				SetRaw("vSmartVPN", "0").
+				Set("version", imageVersion).
+				Set("versionType", "vmanage").
+				SetRaw("reboot", "false").
+				SetRaw("sync", "true").Str).
+			SetRaw("devices", upgradeDevicesBody(devices, "").Str).
+			Set("deviceType", deviceType)
+		installed := client.runUpgradeTask(&result, "install", "/device/action/install", body, devices, deadline, mods...)
+		if !activate || len(installed) == 0 {
+			continue
+		}
+		body = Body{}.
+			Set("action", "changepartition").
+			SetRaw("devices", upgradeDevicesBody(installed, imageVersion).Str).
+			Set("deviceType", deviceType)
+		client.runUpgradeTask(&result, "activate", "/device/action/changepartition", body, installed, deadline, mods...)
+	}
+
+	for _, id := range deviceIDs {
+		if _, ok := result.Devices[id]; !ok {
+			result.Devices[id] = nil
+		}
+	}
+	if failed := result.FailedDevices(); len(failed) > 0 {
+		log.Printf("[ERROR] Software upgrade failed for %v devices: %s", len(failed), strings.Join(failed, ", "))
+		return result, fmt.Errorf("software upgrade failed for %v of %v devices: %s", len(failed), len(deviceIDs), strings.Join(failed, ", "))
+	}
+	return result, nil
+}
+
+// upgradeDevicesBody creates the devices of a software action, the version is only set if not empty.
+func upgradeDevicesBody(devices []upgradeDevice, version string) Body {
+	body := Body{Str: "[]"}
+	for _, device := range devices {
+		entry := Body{}.Set("deviceIP", device.systemIP).Set("deviceId", device.id)
+		if version != "" {
+			entry = entry.Set("version", version)
+		}
+		body = body.SetRaw("-1", entry.Str)
+	}
+	return body
+}
+
+// runUpgradeTask starts a software action task, waits for it and records the outcome of every device.
+// It returns the devices the action succeeded for.
+func (client *Client) runUpgradeTask(result *UpgradeResult, action, path string, body Body, devices []upgradeDevice, deadline time.Time, mods ...func(*Req)) []upgradeDevice {
+	fail := func(err error) []upgradeDevice {
+		for _, device := range devices {
+			result.Devices[device.id] = fmt.Errorf("%s: %w", action, err)
+		}
+		return nil
+	}
+	res, err := client.Post(path, body.Str, mods...)
+	if err != nil {
+		return fail(err)
+	}
+	taskID := res.Get("id").String()
+	if taskID == "" {
+		return fail(fmt.Errorf("%w: no task ID in %s response", ErrUnexpectedSchema, path))
+	}
+	task, err := client.WaitForTask(taskID, deadline.Sub(client.clockNow()), mods...)
+	result.Tasks = append(result.Tasks, task)
+	if err != nil && task.Status != "done" {
+		return fail(err)
+	}
+	succeeded := []upgradeDevice{}
+	for _, device := range devices {
+		activity, ok := task.device(device.id, device.systemIP)
+		switch {
+		case !ok:
+			result.Devices[device.id] = fmt.Errorf("%s: device %s: %w", action, device.id, ErrNotFound)
+		case strings.EqualFold(activity.Status, "failure"):
+			message := ""
+			if len(activity.Messages) > 0 {
+				message = activity.Messages[len(activity.Messages)-1]
+			}
+			result.Devices[device.id] = fmt.Errorf("%s failed: %s", action, message)
+		default:
+			succeeded = append(succeeded, device)
+		}
+	}
+	return succeeded
+}
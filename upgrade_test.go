@@ -0,0 +1,71 @@
+package sdwan
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientUpgradeDevices tests the Client::UpgradeDevices method.
+func TestClientUpgradeDevices(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	withClock(&fakeClock{now: time.Now()})(&client)
+
+	gock.New(testURL).Get("/dataservice/device").Reply(200).BodyString(`{"data":[
+		{"uuid":"DEV1","system-ip":"1.1.1.1","device-type":"vedge"},
+		{"uuid":"DEV2","system-ip":"1.1.1.2","device-type":"vedge"}
+	]}`)
+	gock.New(testURL).Post("/dataservice/device/action/install").
+		BodyString(`{"action":"install","input":{"vEdgeVPN":0,"vSmartVPN":0,"version":"17.9.4","versionType":"vmanage","reboot":false,"sync":true},` +
+			`"devices":[{"deviceIP":"1.1.1.1","deviceId":"DEV1"},{"deviceIP":"1.1.1.2","deviceId":"DEV2"}],"deviceType":"vedge"}`).
+		Reply(200).BodyString(`{"id":"T1"}`)
+	gock.New(testURL).Get("/dataservice/device/action/status/T1").Reply(200).
+		BodyString(`{"summary":{"status":"done"},"data":[{"deviceID":"1.1.1.1","uuid":"DEV1","status":"Success"},{"deviceID":"1.1.1.2","uuid":"DEV2","status":"Failure","activity":["Not enough disk space"]}]}`)
+	gock.New(testURL).Post("/dataservice/device/action/changepartition").
+		BodyString(`{"action":"changepartition","devices":[{"deviceIP":"1.1.1.1","deviceId":"DEV1","version":"17.9.4"}],"deviceType":"vedge"}`).
+		Reply(200).BodyString(`{"id":"T2"}`)
+	gock.New(testURL).Get("/dataservice/device/action/status/T2").Reply(200).
+		BodyString(`{"summary":{"status":"done"},"data":[{"deviceID":"1.1.1.1","status":"Success"}]}`)
+
+	result, err := client.UpgradeDevices([]string{"DEV1", "DEV2", "DEV3"}, "17.9.4", true, time.Minute)
+	assert.EqualError(t, err, "software upgrade failed for 2 of 3 devices: DEV2, DEV3")
+	assert.NoError(t, result.Devices["DEV1"])
+	assert.EqualError(t, result.Devices["DEV2"], "install failed: Not enough disk space")
+	assert.True(t, errors.Is(result.Devices["DEV3"], ErrNotFound))
+	assert.Len(t, result.Tasks, 2)
+	assert.True(t, gock.IsDone())
+
+	// Missing task ID
+	gock.New(testURL).Get("/dataservice/device").Reply(200).BodyString(`{"data":[{"uuid":"DEV1","system-ip":"1.1.1.1","device-type":"vedge"}]}`)
+	gock.New(testURL).Post("/dataservice/device/action/install").Reply(200).BodyString(`{}`)
+	result, err = client.UpgradeDevices([]string{"DEV1"}, "17.9.4", false, time.Minute)
+	assert.Error(t, err)
+	assert.ErrorIs(t, result.Devices["DEV1"], ErrUnexpectedSchema)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientListSoftwareImages tests the Client::ListSoftwareImages method.
+func TestClientListSoftwareImages(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/device/action/software/images").Reply(200).BodyString(`{"data":[
+		{"versionName":"17.09.04a","platformFamily":["isr","c8000v"],"availableFiles":"c8000v-universalk9.17.09.04a.SPA.bin"},
+		{"version":"20.9.1","platformFamily":"vedge, vsmart","fileName":"viptela-20.9.1-x86_64.tar.gz","status":"Uploading"}
+	]}`)
+	images, err := client.ListSoftwareImages()
+	assert.NoError(t, err)
+	assert.Len(t, images, 2)
+	assert.Equal(t, "17.09.04a", images[0].Version)
+	assert.Equal(t, []string{"isr", "c8000v"}, images[0].PlatformFamilies)
+	assert.Equal(t, "c8000v-universalk9.17.09.04a.SPA.bin", images[0].FileName)
+	assert.True(t, images[0].Available)
+	assert.Equal(t, "20.9.1", images[1].Version)
+	assert.Equal(t, []string{"vedge", "vsmart"}, images[1].PlatformFamilies)
+	assert.False(t, images[1].Available)
+	assert.True(t, gock.IsDone())
+}
@@ -0,0 +1,148 @@
+package sdwan
+
+import "net/url"
+
+// User is a local vManage user.
+type User struct {
+	// Username is the login name of the user.
+	Username string
+	// Description is the full name or description of the user.
+	Description string
+	// Groups are the user groups the user belongs to, e.g. "netadmin" or "operator".
+	Groups []string
+	// Locale is the locale of the user, e.g. "en_US".
+	Locale string
+	// ResourceGroup is the resource group of the user, e.g. "global".
+	ResourceGroup string
+	// Res is the complete user object.
+	Res Res
+}
+
+// ParseUser parses a user object retrieved from /admin/user.
+func ParseUser(res Res) User {
+	user := User{
+		Username:      res.Get("userName").String(),
+		Description:   res.Get("description").String(),
+		Locale:        res.Get("locale").String(),
+		ResourceGroup: res.Get("resGroupName").String(),
+		Res:           res,
+	}
+	for _, group := range res.Get("group").Array() {
+		user.Groups = append(user.Groups, group.String())
+	}
+	return user
+}
+
+// UserSpec describes a local vManage user to be created or updated.
+type UserSpec struct {
+	// Username is the login name of the user.
+	Username string
+	// Password is the password of a new user, it is not changed by UpdateUser.
+	Password string
+	// Description is the full name or description of the user.
+	Description string
+	// Groups are the user groups the user belongs to, e.g. "netadmin" or "operator".
+	Groups []string
+	// Locale is the locale of the user, defaults to "en_US".
+	Locale string
+	// ResourceGroup is the resource group of the user, defaults to "global".
+	ResourceGroup string
+}
+
+// Body creates the user request body, the password is only included if set.
+func (spec UserSpec) Body() Body {
+	locale := spec.Locale
+	if locale == "" {
+		locale = "en_US"
+	}
+	resourceGroup := spec.ResourceGroup
+	if resourceGroup == "" {
+		resourceGroup = "global"
+	}
+	body := Body{}.
+		Set("userName", spec.Username).
+		Set("description", spec.Description).
+		Set("locale", locale).
+		Set("resGroupName", resourceGroup).
+		SetRaw("group", "[]")
+	for _, group := range spec.Groups {
+		body = body.Set("group.-1", group)
+	}
+	if spec.Password != "" {
+		body = body.Set("password", spec.Password)
+	}
+	return body
+}
+
+// UserGroupTask is the permission of a user group for a feature.
+type UserGroupTask struct {
+	// Feature is the name of the feature, e.g. "Device Inventory".
+	Feature string
+	// Read indicates whether the group has read access.
+	Read bool
+	// Write indicates whether the group has write access.
+	Write bool
+}
+
+// UserGroup is a vManage user group.
+type UserGroup struct {
+	// Name is the name of the group.
+	Name string
+	// Tasks are the permissions of the group.
+	Tasks []UserGroupTask
+	// Res is the complete user group object.
+	Res Res
+}
+
+// ListUsers retrieves all local vManage users.
+func (client *Client) ListUsers(mods ...func(*Req)) ([]User, error) {
+	results, err := client.GetData("/admin/user", mods...)
+	if err != nil {
+		return nil, err
+	}
+	users := make([]User, len(results))
+	for i, result := range results {
+		users[i] = ParseUser(result)
+	}
+	return users, nil
+}
+
+// CreateUser creates a local vManage user.
+func (client *Client) CreateUser(spec UserSpec, mods ...func(*Req)) error {
+	_, err := client.Post("/admin/user", spec.Body().Str, append([]func(*Req){NoLogPayload}, mods...)...)
+	return err
+}
+
+// UpdateUser updates the description, groups, locale and resource group of a local vManage user.
+// The password is not changed.
+func (client *Client) UpdateUser(spec UserSpec, mods ...func(*Req)) error {
+	spec.Password = ""
+	_, err := client.Put("/admin/user/"+url.PathEscape(spec.Username), spec.Body().Str, mods...)
+	return err
+}
+
+// DeleteUser deletes a local vManage user.
+func (client *Client) DeleteUser(username string, mods ...func(*Req)) error {
+	_, err := client.Delete("/admin/user/"+url.PathEscape(username), mods...)
+	return err
+}
+
+// ListUserGroups retrieves all vManage user groups including their permissions.
+func (client *Client) ListUserGroups(mods ...func(*Req)) ([]UserGroup, error) {
+	results, err := client.GetData("/admin/usergroup", mods...)
+	if err != nil {
+		return nil, err
+	}
+	groups := make([]UserGroup, len(results))
+	for i, result := range results {
+		groups[i] = UserGroup{Name: result.Get("groupName").String(), Res: result}
+		for _, task := range result.Get("tasks").Array() {
+			groups[i].Tasks = append(groups[i].Tasks, UserGroupTask{
+				Feature: task.Get("feature").String(),
+				Read:    task.Get("read").Bool(),
+				Write:   task.Get("write").Bool(),
+			})
+		}
+	}
+	return groups, nil
+}
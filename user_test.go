@@ -0,0 +1,58 @@
+package sdwan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+// TestClientListUsers tests the Client::ListUsers method.
+func TestClientListUsers(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/admin/user").Reply(200).
+		BodyString(`{"data":[{"userName":"jdoe","description":"John Doe","group":["netadmin"],"locale":"en_US","resGroupName":"global"}]}`)
+	users, err := client.ListUsers()
+	assert.NoError(t, err)
+	assert.Equal(t, "jdoe", users[0].Username)
+	assert.Equal(t, []string{"netadmin"}, users[0].Groups)
+	assert.Equal(t, "global", users[0].ResourceGroup)
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientUserCRUD tests the Client::CreateUser, Client::UpdateUser and Client::DeleteUser methods.
+func TestClientUserCRUD(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+	spec := UserSpec{Username: "jdoe", Password: "secret", Description: "John Doe", Groups: []string{"operator"}}
+
+	gock.New(testURL).Post("/dataservice/admin/user").
+		BodyString(`{"userName":"jdoe","description":"John Doe","locale":"en_US","resGroupName":"global","group":["operator"],"password":"secret"}`).
+		Reply(200)
+	assert.NoError(t, client.CreateUser(spec))
+
+	gock.New(testURL).Put("/dataservice/admin/user/jdoe").
+		BodyString(`{"userName":"jdoe","description":"John Doe","locale":"en_US","resGroupName":"global","group":["operator"]}`).
+		Reply(200)
+	assert.NoError(t, client.UpdateUser(spec))
+
+	gock.New(testURL).Delete("/dataservice/admin/user/jdoe").Reply(200)
+	assert.NoError(t, client.DeleteUser("jdoe"))
+	assert.True(t, gock.IsDone())
+}
+
+// TestClientListUserGroups tests the Client::ListUserGroups method.
+func TestClientListUserGroups(t *testing.T) {
+	defer gock.Off()
+	client := authenticatedTestClient()
+
+	gock.New(testURL).Get("/dataservice/admin/usergroup").Reply(200).
+		BodyString(`{"data":[{"groupName":"operator","tasks":[{"feature":"Device Inventory","read":true,"write":false}]}]}`)
+	groups, err := client.ListUserGroups()
+	assert.NoError(t, err)
+	assert.Equal(t, "operator", groups[0].Name)
+	assert.Equal(t, []UserGroupTask{{Feature: "Device Inventory", Read: true}}, groups[0].Tasks)
+	assert.True(t, gock.IsDone())
+}